@@ -27,9 +27,12 @@ func main() {
 	}
 	defer out.Close()
 
-	time.Sleep(1 * time.Second)
+	path, err := out.EventPath("/dev/input", 5*time.Second)
+	if err != nil {
+		log.Fatalf("faied to find evdev device: %v", err)
+	}
 
-	in, err := evdev.OpenByName("/dev/input", "Chromebook", 9)
+	in, err := evdev.OpenDevice(path)
 	if err != nil {
 		log.Fatalf("faied to open evdev device: %v", err)
 	}
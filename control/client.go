@@ -0,0 +1,75 @@
+package control
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+
+	"github.com/erdichen/chromekey/remap"
+	"github.com/erdichen/chromekey/remap/config"
+)
+
+// Call sends a request to the control socket of a remapper and returns its response.
+func Call(path string, req *config.ControlRequest) (*config.ControlResponse, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := writeMessage(conn, req); err != nil {
+		return nil, err
+	}
+	var resp config.ControlResponse
+	if err := readMessage(conn, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
+
+// Subscription is a stream of the state changes of a remapper.
+type Subscription struct {
+	conn net.Conn
+	dec  *json.Decoder
+}
+
+// Subscribe connects to the control socket of a remapper and subscribes to its state changes.
+// The first event is the current state.
+func Subscribe(path string) (*Subscription, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	req := &config.ControlRequest{Op: &config.ControlRequest_Subscribe{Subscribe: &config.Empty{}}}
+	if err := writeMessage(conn, req); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	var resp config.ControlResponse
+	if err := readMessage(conn, &resp); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if resp.Error != "" {
+		conn.Close()
+		return nil, errors.New(resp.Error)
+	}
+	return &Subscription{conn: conn, dec: json.NewDecoder(conn)}, nil
+}
+
+// Next waits for the next state change.
+func (sub *Subscription) Next() (*remap.Event, error) {
+	var ev remap.Event
+	if err := sub.dec.Decode(&ev); err != nil {
+		return nil, err
+	}
+	return &ev, nil
+}
+
+// Close ends the subscription.
+func (sub *Subscription) Close() error {
+	return sub.conn.Close()
+}
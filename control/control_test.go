@@ -0,0 +1,80 @@
+package control
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/erdichen/chromekey/remap/config"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMessage(t *testing.T) {
+	var buf bytes.Buffer
+	want := &config.ControlRequest{Op: &config.ControlRequest_SetFnLock{SetFnLock: &config.SetFnLock{On: true}}}
+	if err := writeMessage(&buf, want); err != nil {
+		t.Fatalf("writeMessage: %v", err)
+	}
+	var got config.ControlRequest
+	if err := readMessage(&buf, &got); err != nil {
+		t.Fatalf("readMessage: %v", err)
+	}
+	if !proto.Equal(&got, want) {
+		t.Errorf("got %v want %v", &got, want)
+	}
+}
+
+func TestCheckPeer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ctl.sock")
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer l.Close()
+	go func() {
+		if c, err := net.Dial("unix", path); err == nil {
+			defer c.Close()
+			c.Read(make([]byte, 1))
+		}
+	}()
+	conn, err := l.AcceptUnix()
+	if err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	defer conn.Close()
+
+	// The user running the server is allowed.
+	srv := &Server{}
+	if err := srv.checkPeer(conn); err != nil {
+		t.Errorf("checkPeer: %v (uid %d)", err, os.Getuid())
+	}
+}
+
+func TestListen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ctl.sock")
+
+	// A socket left by a stopped instance is replaced.
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	l.SetUnlinkOnClose(false)
+	l.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv := &Server{}
+	if err := srv.Listen(ctx, path); err != nil {
+		t.Fatalf("Listen with a stale socket: %v", err)
+	}
+
+	// A socket of a running instance is kept.
+	if err := srv.Listen(ctx, path); err == nil {
+		t.Errorf("Listen with a running instance: got no error")
+	}
+	if _, err := os.Lstat(path); err != nil {
+		t.Errorf("Listen with a running instance: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/erdichen/chromekey/remap"
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/godbus/dbus/v5/prop"
+)
+
+// The D-Bus name, object path and interface of a remapper.
+const (
+	DBusName  = "io.github.erdichen.Chromekey"
+	DBusPath  = dbus.ObjectPath("/io/github/erdichen/Chromekey")
+	DBusIface = "io.github.erdichen.Chromekey"
+)
+
+// dbusObject implements the methods of the D-Bus interface.
+type dbusObject struct {
+	ctx context.Context
+	s   *remap.State
+}
+
+// ToggleFn toggles the FN lock state and returns the new state.
+func (o *dbusObject) ToggleFn() (bool, *dbus.Error) {
+	on := false
+	if err := o.setFnLock(func(st remap.Status) bool { on = !st.FnLock; return on }); err != nil {
+		return false, dbus.MakeFailedError(err)
+	}
+	return on, nil
+}
+
+// setFnLock sets the FN lock state returned by f given the current state.
+func (o *dbusObject) setFnLock(f func(st remap.Status) bool) error {
+	found := false
+	if err := o.s.Exec(o.ctx, func() { found = o.s.SetFnLock(f(o.s.Status())) }); err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("the configuration has no FN lock layer")
+	}
+	return nil
+}
+
+// ExportDBus exports the FN lock and layer state of a remapper on a D-Bus connection and requests the remapper's name.
+// The properties follow the state changes of the remapper until ctx is done.
+func ExportDBus(ctx context.Context, conn *dbus.Conn, s *remap.State) error {
+	var c <-chan remap.Event
+	if err := s.Exec(ctx, func() { c = s.Subscribe() }); err != nil {
+		return err
+	}
+	ok := false
+	defer func() {
+		if !ok {
+			s.Exec(ctx, func() { s.Unsubscribe(c) })
+		}
+	}()
+	// The first event is the current state.
+	st := (<-c).Status
+
+	o := &dbusObject{ctx: ctx, s: s}
+	props, err := prop.Export(conn, DBusPath, prop.Map{
+		DBusIface: {
+			"FnLock": {
+				Value:    st.FnLock,
+				Writable: true,
+				Emit:     prop.EmitTrue,
+				Callback: func(c *prop.Change) *dbus.Error {
+					on, _ := c.Value.(bool)
+					if err := o.setFnLock(func(remap.Status) bool { return on }); err != nil {
+						return dbus.MakeFailedError(err)
+					}
+					return nil
+				},
+			},
+			"ActiveLayers": {Value: activeLayers(st), Emit: prop.EmitTrue},
+			"Paused":       {Value: st.Paused, Emit: prop.EmitTrue},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := conn.Export(o, DBusPath, DBusIface); err != nil {
+		return err
+	}
+	node := &introspect.Node{
+		Name: string(DBusPath),
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			prop.IntrospectData,
+			{
+				Name:       DBusIface,
+				Methods:    introspect.Methods(o),
+				Properties: props.Introspection(DBusIface),
+			},
+		},
+	}
+	if err := conn.Export(introspect.NewIntrospectable(node), DBusPath, "org.freedesktop.DBus.Introspectable"); err != nil {
+		return err
+	}
+
+	reply, err := conn.RequestName(DBusName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		return err
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		return fmt.Errorf("D-Bus name %s is already taken", DBusName)
+	}
+	ok = true
+
+	go func() {
+		for {
+			select {
+			case ev := <-c:
+				setProp(props, "FnLock", ev.Status.FnLock)
+				setProp(props, "ActiveLayers", activeLayers(ev.Status))
+				setProp(props, "Paused", ev.Status.Paused)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// setProp sets a property that has changed, which emits a PropertiesChanged signal.
+func setProp(props *prop.Properties, name string, v interface{}) {
+	if !reflect.DeepEqual(props.GetMust(DBusIface, name), v) {
+		props.SetMust(DBusIface, name, v)
+	}
+}
+
+// activeLayers returns the toggled layers of the top-level key maps as a D-Bus string array.
+// The events of the remapper leave out the momentary layers, which would reveal the timing of key presses.
+func activeLayers(st remap.Status) []string {
+	return append([]string{}, st.ActiveLayers...)
+}
@@ -0,0 +1,128 @@
+package control
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erdichen/chromekey/remap"
+	"github.com/erdichen/chromekey/remap/config"
+	"github.com/godbus/dbus/v5"
+)
+
+const busConfig = `<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:path=%s</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`
+
+// startBus starts a private dbus-daemon and returns its address.
+// The daemon is found in $DBUS_DAEMON or $PATH, otherwise the test is skipped.
+func startBus(t *testing.T) string {
+	daemon := os.Getenv("DBUS_DAEMON")
+	if daemon == "" {
+		var err error
+		if daemon, err = exec.LookPath("dbus-daemon"); err != nil {
+			t.Skip("dbus-daemon not found")
+		}
+	}
+	dir := t.TempDir()
+	cfg := filepath.Join(dir, "bus.conf")
+	if err := ioutil.WriteFile(cfg, []byte(strings.Replace(busConfig, "%s", filepath.Join(dir, "bus"), 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(daemon, "--config-file="+cfg, "--nofork", "--print-address")
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	addr, err := bufio.NewReader(out).ReadString('\n')
+	if err != nil {
+		t.Fatalf("failed to read the dbus-daemon address: %v", err)
+	}
+	return strings.TrimSpace(addr)
+}
+
+func TestDBus(t *testing.T) {
+	addr := startBus(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A remapper without input devices.
+	cfg := config.DefaultRunConfig()
+	cfg.OutputMode = config.OutputMode_PER_DEVICE_OUTPUT
+	s, err := remap.New(ctx, nil, cfg, remap.Options{})
+	if err != nil {
+		t.Fatalf("remap.New: %v", err)
+	}
+	go s.Start(ctx, nil, 0)
+
+	srv, err := dbus.Connect(addr)
+	if err != nil {
+		t.Fatalf("failed to connect the service: %v", err)
+	}
+	defer srv.Close()
+	if err := ExportDBus(ctx, srv, s); err != nil {
+		t.Fatalf("ExportDBus: %v", err)
+	}
+
+	cli, err := dbus.Connect(addr)
+	if err != nil {
+		t.Fatalf("failed to connect the client: %v", err)
+	}
+	defer cli.Close()
+	if err := cli.AddMatchSignal(dbus.WithMatchObjectPath(DBusPath), dbus.WithMatchInterface("org.freedesktop.DBus.Properties"), dbus.WithMatchMember("PropertiesChanged")); err != nil {
+		t.Fatalf("AddMatchSignal: %v", err)
+	}
+	sigC := make(chan *dbus.Signal, 10)
+	cli.Signal(sigC)
+
+	obj := cli.Object(DBusName, DBusPath)
+	var on bool
+	if err := obj.Call(DBusIface+".ToggleFn", 0).Store(&on); err != nil || !on {
+		t.Fatalf("ToggleFn: got %v, %v want true", on, err)
+	}
+
+	select {
+	case sig := <-sigC:
+		changed, _ := sig.Body[1].(map[string]dbus.Variant)
+		if v, ok := changed["FnLock"]; !ok || v.Value() != true {
+			t.Errorf("PropertiesChanged: got %v want FnLock true", sig.Body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("no PropertiesChanged signal")
+	}
+
+	if err := obj.SetProperty(DBusIface+".FnLock", dbus.MakeVariant(false)); err != nil {
+		t.Fatalf("SetProperty: %v", err)
+	}
+	var st remap.Status
+	if err := s.Exec(ctx, func() { st = s.Status() }); err != nil || st.FnLock {
+		t.Errorf("FN lock after SetProperty: got %v, %v want false", st.FnLock, err)
+	}
+	v, err := obj.GetProperty(DBusIface + ".FnLock")
+	if err != nil || v.Value() != false {
+		t.Errorf("GetProperty: got %v, %v want false", v, err)
+	}
+}
@@ -0,0 +1,238 @@
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/user"
+	"strconv"
+
+	"github.com/erdichen/chromekey/evdev/keycode"
+	"github.com/erdichen/chromekey/log"
+	"github.com/erdichen/chromekey/remap"
+	"github.com/erdichen/chromekey/remap/config"
+	"golang.org/x/sys/unix"
+)
+
+// Server serves the control API of a remapper on a Unix domain socket.
+// Only root, the user running the server and the members of AllowGIDs may connect.
+type Server struct {
+	State     *remap.State
+	Reload    func() (config.RunConfig, error) // Loads the configuration file, nil if there is none.
+	AllowGIDs []uint32
+}
+
+// Listen creates the control socket and serves it until ctx is done.
+func (srv *Server) Listen(ctx context.Context, path string) error {
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		// Remove the socket left by a previous instance unless it is still running.
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return fmt.Errorf("control socket %v is in use by another instance", path)
+		}
+		os.Remove(path)
+	}
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		return err
+	}
+	// Access is checked with the peer credentials of each connection.
+	if err := os.Chmod(path, 0666); err != nil {
+		l.Close()
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+	go func() {
+		for {
+			conn, err := l.AcceptUnix()
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Errorf("failed to accept control connection: %v", err)
+				}
+				return
+			}
+			go srv.serve(ctx, conn)
+		}
+	}()
+	return nil
+}
+
+// serve answers the requests of a connection until it is closed.
+func (srv *Server) serve(ctx context.Context, conn *net.UnixConn) {
+	defer conn.Close()
+	if err := srv.checkPeer(conn); err != nil {
+		log.Errorf("rejected control connection: %v", err)
+		return
+	}
+	for {
+		var req config.ControlRequest
+		if err := readMessage(conn, &req); err != nil {
+			if err != io.EOF {
+				log.Errorf("failed to read control request: %v", err)
+			}
+			return
+		}
+		if _, ok := req.Op.(*config.ControlRequest_Subscribe); ok {
+			srv.stream(ctx, conn)
+			return
+		}
+		resp, err := srv.handle(ctx, &req)
+		if err != nil {
+			resp = &config.ControlResponse{Error: err.Error()}
+		}
+		if err := writeMessage(conn, resp); err != nil {
+			log.Errorf("failed to write control response: %v", err)
+			return
+		}
+	}
+}
+
+// stream writes the state changes of the remapper to a connection as JSON lines until the connection is closed.
+func (srv *Server) stream(ctx context.Context, conn *net.UnixConn) {
+	s := srv.State
+	var c <-chan remap.Event
+	if err := s.Exec(ctx, func() { c = s.Subscribe() }); err != nil {
+		return
+	}
+	defer s.Exec(ctx, func() { s.Unsubscribe(c) })
+
+	if err := writeMessage(conn, &config.ControlResponse{}); err != nil {
+		return
+	}
+	// The client sends nothing more, so a read returns when the connection is closed.
+	closed := make(chan struct{})
+	go func() {
+		conn.Read(make([]byte, 1))
+		close(closed)
+	}()
+	enc := json.NewEncoder(conn)
+	for {
+		select {
+		case ev := <-c:
+			if err := enc.Encode(ev); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkPeer returns an error if the process on the other end of a connection is not allowed to control the remapper.
+func (srv *Server) checkPeer(conn *net.UnixConn) error {
+	rc, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var cred *unix.Ucred
+	var credErr error
+	if err := rc.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil {
+		return err
+	}
+	if credErr != nil {
+		return credErr
+	}
+	if cred.Uid == 0 || int(cred.Uid) == os.Getuid() {
+		return nil
+	}
+	gids := []uint32{cred.Gid}
+	if u, err := user.LookupId(strconv.Itoa(int(cred.Uid))); err == nil {
+		if groups, err := u.GroupIds(); err == nil {
+			for _, g := range groups {
+				if gid, err := strconv.ParseUint(g, 10, 32); err == nil {
+					gids = append(gids, uint32(gid))
+				}
+			}
+		}
+	}
+	for _, allowed := range srv.AllowGIDs {
+		for _, gid := range gids {
+			if gid == allowed {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("pid %d uid %d is not allowed", cred.Pid, cred.Uid)
+}
+
+// handle runs a request in the event loop of the remapper.
+func (srv *Server) handle(ctx context.Context, req *config.ControlRequest) (*config.ControlResponse, error) {
+	s := srv.State
+	resp := &config.ControlResponse{}
+	var err error
+	switch op := req.Op.(type) {
+	case *config.ControlRequest_GetStatus:
+		var st remap.Status
+		err = s.Exec(ctx, func() { st = s.Status() })
+		resp.Status = ToPBStatus(st)
+	case *config.ControlRequest_SetFnLock:
+		found := false
+		err = s.Exec(ctx, func() { found = s.SetFnLock(op.SetFnLock.GetOn()) })
+		if err == nil && !found {
+			err = errors.New("the configuration has no FN lock layer")
+		}
+	case *config.ControlRequest_GetConfig:
+		var cfg config.RunConfig
+		err = s.Exec(ctx, func() { cfg = s.Config() })
+		resp.Config = config.ToPBConfig(cfg)
+	case *config.ControlRequest_SetConfig:
+		cfg := config.FromPBConfig(op.SetConfig)
+		err = s.Exec(ctx, func() { s.SetConfig(cfg) })
+	case *config.ControlRequest_Reload:
+		if srv.Reload == nil {
+			return nil, errors.New("no configuration file to reload")
+		}
+		cfg, loadErr := srv.Reload()
+		err = s.Exec(ctx, func() {
+			if loadErr == nil {
+				s.SetConfig(cfg)
+			}
+			s.SetConfigError(loadErr != nil)
+		})
+		if err == nil && loadErr != nil {
+			err = fmt.Errorf("failed to reload configuration: %v", loadErr)
+		}
+	case *config.ControlRequest_Pause:
+		err = s.Exec(ctx, s.Pause)
+	case *config.ControlRequest_Resume:
+		err = s.Exec(ctx, s.Resume)
+	default:
+		err = errors.New("unknown control request")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ToPBStatus converts the status of a remapper to its protobuf form.
+func ToPBStatus(st remap.Status) *config.Status {
+	pb := &config.Status{
+		FnLock:      st.FnLock,
+		Paused:      st.Paused,
+		ConfigError: st.ConfigError,
+		PressedKey:  append([]keycode.Code(nil), st.PressedKeys...),
+		ActiveLayer: append([]string(nil), st.ActiveLayers...),
+	}
+	for _, d := range st.Devices {
+		pb.Device = append(pb.Device, &config.DeviceStatus{
+			Path:        d.Info.Path,
+			Name:        d.Info.Name,
+			Id:          fmt.Sprintf("%04x:%04x", d.Info.ID.Vendor, d.Info.ID.Product),
+			FnLock:      d.FnLock,
+			ActiveLayer: append([]string(nil), d.ActiveLayers...),
+		})
+	}
+	return pb
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/erdichen/chromekey/control"
+	"github.com/erdichen/chromekey/remap/config"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+const ctlUsage = `Commands of a running remapper:
+
+  status            Print the FN lock state, active layers, pressed keys and input devices
+  fn on|off         Set the FN lock state
+  config get        Print the running configuration
+  config set FILE   Replace the running configuration until the next reload
+  reload            Load the configuration file again
+  pause             Forward keys without remapping
+  resume            Remap keys again
+`
+
+// runCtl sends a command to the control socket of a running remapper and prints the response.
+func runCtl(socket string, args []string) error {
+	req := &config.ControlRequest{}
+	cmd := ""
+	if len(args) > 0 {
+		cmd = args[0]
+	}
+	switch {
+	case cmd == "status" && len(args) == 1:
+		req.Op = &config.ControlRequest_GetStatus{GetStatus: &config.Empty{}}
+	case cmd == "fn" && len(args) == 2 && (args[1] == "on" || args[1] == "off"):
+		req.Op = &config.ControlRequest_SetFnLock{SetFnLock: &config.SetFnLock{On: args[1] == "on"}}
+	case cmd == "config" && len(args) == 2 && args[1] == "get":
+		req.Op = &config.ControlRequest_GetConfig{GetConfig: &config.Empty{}}
+	case cmd == "config" && len(args) == 3 && args[1] == "set":
+		b, err := ioutil.ReadFile(args[2])
+		if err != nil {
+			return err
+		}
+		var pb config.KeymapConfig
+		if err := prototext.Unmarshal(b, &pb); err != nil {
+			return fmt.Errorf("failed to unmarshal configuration proto: %v", err)
+		}
+		req.Op = &config.ControlRequest_SetConfig{SetConfig: &pb}
+	case cmd == "reload" && len(args) == 1:
+		req.Op = &config.ControlRequest_Reload{Reload: &config.Empty{}}
+	case cmd == "pause" && len(args) == 1:
+		req.Op = &config.ControlRequest_Pause{Pause: &config.Empty{}}
+	case cmd == "resume" && len(args) == 1:
+		req.Op = &config.ControlRequest_Resume{Resume: &config.Empty{}}
+	case cmd == "help":
+		fmt.Print(ctlUsage)
+		return nil
+	default:
+		return errors.New(ctlUsage)
+	}
+
+	if socket == "" {
+		return errors.New("the control socket is disabled, set -control_socket")
+	}
+	resp, err := control.Call(socket, req)
+	if err != nil {
+		return err
+	}
+	opts := prototext.MarshalOptions{Indent: "  "}
+	switch {
+	case resp.Status != nil:
+		_, err = os.Stdout.WriteString(opts.Format(resp.Status))
+	case resp.Config != nil:
+		_, err = os.Stdout.WriteString(opts.Format(resp.Config))
+	}
+	return err
+}
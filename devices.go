@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/erdichen/chromekey/evdev"
+	"github.com/erdichen/chromekey/evdev/keycode"
+)
+
+// deviceEntry is an input device in the output of the devices command.
+type deviceEntry struct {
+	Path       string      `json:"path"`
+	Info       *evdev.Info `json:"info,omitempty"`
+	EventTypes []string    `json:"event_types,omitempty"`
+	Keys       int         `json:"keys"`
+	LEDs       []string    `json:"leds,omitempty"`
+	Selected   bool        `json:"selected"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// listDevices prints the event devices in a directory and whether the remapper selects them.
+// The select function is the selection of the remapper given all the event devices of the directory.
+func listDevices(devDir string, selectDevs func([]*evdev.Device) []*evdev.Device, args []string) error {
+	fs := flag.NewFlagSet("devices", flag.ContinueOnError)
+	jsonOut := fs.Bool("json", false, "Print the devices in JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	devs, errList, err := evdev.OpenAll(devDir)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, d := range devs {
+			d.Close()
+		}
+	}()
+
+	selected := map[*evdev.Device]bool{}
+	for _, d := range selectDevs(devs) {
+		selected[d] = true
+	}
+	entries := []*deviceEntry{}
+	for _, d := range devs {
+		entries = append(entries, describeDevice(d, selected[d]))
+	}
+	for _, err := range errList {
+		e := &deviceEntry{Error: err.Error()}
+		var pe *os.PathError
+		if errors.As(err, &pe) {
+			e.Path, e.Error = pe.Path, pe.Err.Error()
+		}
+		entries = append(entries, e)
+	}
+	sortEntries(entries)
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	printEntries(os.Stdout, entries)
+	return nil
+}
+
+// sortEntries sorts device entries by their event device numbers, e.g. event2 before event10.
+func sortEntries(entries []*deviceEntry) {
+	num := func(path string) int {
+		n, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(path), "event"))
+		if err != nil {
+			return -1
+		}
+		return n
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return num(entries[i].Path) < num(entries[j].Path)
+	})
+}
+
+// printEntries prints device entries in a human readable format.
+func printEntries(w io.Writer, entries []*deviceEntry) {
+	for _, e := range entries {
+		if e.Error != "" {
+			fmt.Fprintf(w, "%s: %s\n", e.Path, e.Error)
+			continue
+		}
+		action := "skip"
+		if e.Selected {
+			action = "select"
+		}
+		fmt.Fprintf(w, "%s: %q %s\n", e.Path, e.Info.Name, action)
+		fmt.Fprintf(w, "  id %04x:%04x bus %v version %#x phys %q uniq %q\n", e.Info.ID.Vendor, e.Info.ID.Product, keycode.Bus(e.Info.ID.BusType), e.Info.ID.Version, e.Info.Phys, e.Info.Uniq)
+		line := fmt.Sprintf("  events %s, %d keys, LEDs %s", strings.Join(e.EventTypes, " "), e.Keys, strings.Join(e.LEDs, " "))
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+}
+
+// describeDevice returns the identity and capabilities of an event device.
+func describeDevice(d *evdev.Device, selected bool) *deviceEntry {
+	e := &deviceEntry{Path: d.Path(), Selected: selected}
+	var err error
+	if e.Info, err = d.Info(); err != nil {
+		e.Error = err.Error()
+		return e
+	}
+	if types, err := d.GetEventTypes(); err == nil {
+		for _, t := range types {
+			e.EventTypes = append(e.EventTypes, t.String())
+		}
+	}
+	if bits, err := d.GetKeyBits(); err == nil {
+		for k := keycode.Code(0); k < keycode.Code_KEY_CNT; k++ {
+			if bits.Get(k) {
+				e.Keys++
+			}
+		}
+	}
+	if leds, err := d.GetLEDBits(); err == nil {
+		for _, l := range leds.Codes() {
+			e.LEDs = append(e.LEDs, l.String())
+		}
+	}
+	return e
+}
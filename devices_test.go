@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/erdichen/chromekey/evdev"
+)
+
+func TestSortEntries(t *testing.T) {
+	entries := []*deviceEntry{
+		{Path: "/dev/input/event10"},
+		{Path: "/dev/input/event2"},
+		{Path: "/dev/input/event1"},
+	}
+	sortEntries(entries)
+	want := []string{"/dev/input/event1", "/dev/input/event2", "/dev/input/event10"}
+	for i, e := range entries {
+		if e.Path != want[i] {
+			t.Errorf("sortEntries: entry %d got %s want %s", i, e.Path, want[i])
+		}
+	}
+}
+
+func TestPrintEntries(t *testing.T) {
+	entries := []*deviceEntry{
+		{
+			Path:       "/dev/input/event2",
+			Info:       &evdev.Info{Name: "AT Translated Set 2 keyboard", Phys: "isa0060/serio0/input0", ID: evdev.InputID{BusType: 0x11, Vendor: 1, Product: 1, Version: 0xab83}},
+			EventTypes: []string{"EV_SYN", "EV_KEY"},
+			Keys:       100,
+			LEDs:       []string{"LED_CAPSL"},
+			Selected:   true,
+		},
+		{
+			Path: "/dev/input/event3",
+			Info: &evdev.Info{Name: "Power Button"},
+			Keys: 1,
+		},
+		{Path: "/dev/input/event4", Error: "permission denied"},
+	}
+	var b bytes.Buffer
+	printEntries(&b, entries)
+	want := `/dev/input/event2: "AT Translated Set 2 keyboard" select
+  id 0001:0001 bus BUS_I8042 version 0xab83 phys "isa0060/serio0/input0" uniq ""
+  events EV_SYN EV_KEY, 100 keys, LEDs LED_CAPSL
+/dev/input/event3: "Power Button" skip
+  id 0000:0000 bus BUS_NONE version 0x0 phys "" uniq ""
+  events , 1 keys, LEDs
+/dev/input/event4: permission denied
+`
+	if got := b.String(); got != want {
+		t.Errorf("printEntries: got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,235 @@
+package evdev
+
+import (
+	"unsafe"
+
+	"github.com/erdichen/chromekey/evdev/eventcode"
+	"github.com/erdichen/chromekey/evdev/keycode"
+	"github.com/erdichen/chromekey/ioc"
+)
+
+// setBits returns the indexes of the set bits of a bit field.
+func setBits(bits keycode.BitField, cnt uint) []uint {
+	var idx []uint
+	for i := uint(0); i < cnt; i++ {
+		if bits.GetDefault(i, false) {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// RelBits is a set of relative axes.
+type RelBits struct{ bits keycode.BitField }
+
+func (b RelBits) Has(c keycode.Rel) bool { return b.bits.GetDefault(uint(c), false) }
+
+func (b RelBits) Codes() []keycode.Rel {
+	var codes []keycode.Rel
+	for _, i := range setBits(b.bits, uint(keycode.Rel_REL_CNT)) {
+		codes = append(codes, keycode.Rel(i))
+	}
+	return codes
+}
+
+// AbsBits is a set of absolute axes.
+type AbsBits struct{ bits keycode.BitField }
+
+func (b AbsBits) Has(c keycode.Abs) bool { return b.bits.GetDefault(uint(c), false) }
+
+func (b AbsBits) Codes() []keycode.Abs {
+	var codes []keycode.Abs
+	for _, i := range setBits(b.bits, uint(keycode.Abs_ABS_CNT)) {
+		codes = append(codes, keycode.Abs(i))
+	}
+	return codes
+}
+
+// SwitchBits is a set of switches.
+type SwitchBits struct{ bits keycode.BitField }
+
+func (b SwitchBits) Has(c keycode.Switch) bool { return b.bits.GetDefault(uint(c), false) }
+
+func (b SwitchBits) Codes() []keycode.Switch {
+	var codes []keycode.Switch
+	for _, i := range setBits(b.bits, uint(keycode.Switch_SW_CNT)) {
+		codes = append(codes, keycode.Switch(i))
+	}
+	return codes
+}
+
+// MiscBits is a set of misc event codes.
+type MiscBits struct{ bits keycode.BitField }
+
+func (b MiscBits) Has(c eventcode.MiscEvent) bool { return b.bits.GetDefault(uint(c), false) }
+
+func (b MiscBits) Codes() []eventcode.MiscEvent {
+	var codes []eventcode.MiscEvent
+	for _, i := range setBits(b.bits, uint(eventcode.MSC_CNT)) {
+		codes = append(codes, eventcode.MiscEvent(i))
+	}
+	return codes
+}
+
+// SoundBits is a set of sounds.
+type SoundBits struct{ bits keycode.BitField }
+
+func (b SoundBits) Has(c keycode.Sound) bool { return b.bits.GetDefault(uint(c), false) }
+
+func (b SoundBits) Codes() []keycode.Sound {
+	var codes []keycode.Sound
+	for _, i := range setBits(b.bits, uint(keycode.Sound_SND_CNT)) {
+		codes = append(codes, keycode.Sound(i))
+	}
+	return codes
+}
+
+// LEDBits is a set of LEDs.
+type LEDBits struct{ bits keycode.BitField }
+
+func (b LEDBits) Has(c keycode.LED) bool { return b.bits.GetDefault(uint(c), false) }
+
+func (b LEDBits) Codes() []keycode.LED {
+	var codes []keycode.LED
+	for _, i := range setBits(b.bits, uint(keycode.LED_CNT)) {
+		codes = append(codes, keycode.LED(i))
+	}
+	return codes
+}
+
+// GetRelBits returns the relative axes supported by the device.
+func (in *Device) GetRelBits() (RelBits, error) {
+	bits, err := in.getBits(uint(eventcode.EV_REL), uint(keycode.Rel_REL_CNT))
+	return RelBits{bits}, err
+}
+
+// GetAbsBits returns the absolute axes supported by the device.
+func (in *Device) GetAbsBits() (AbsBits, error) {
+	bits, err := in.getBits(uint(eventcode.EV_ABS), uint(keycode.Abs_ABS_CNT))
+	return AbsBits{bits}, err
+}
+
+// GetSwitchBits returns the switches supported by the device.
+func (in *Device) GetSwitchBits() (SwitchBits, error) {
+	bits, err := in.getBits(uint(eventcode.EV_SW), uint(keycode.Switch_SW_CNT))
+	return SwitchBits{bits}, err
+}
+
+// GetMiscBits returns the misc event codes supported by the device.
+func (in *Device) GetMiscBits() (MiscBits, error) {
+	bits, err := in.getBits(uint(eventcode.EV_MSC), uint(eventcode.MSC_CNT))
+	return MiscBits{bits}, err
+}
+
+// GetSoundBits returns the sounds supported by the device.
+func (in *Device) GetSoundBits() (SoundBits, error) {
+	bits, err := in.getBits(uint(eventcode.EV_SND), uint(keycode.Sound_SND_CNT))
+	return SoundBits{bits}, err
+}
+
+// GetLEDBits returns the LEDs supported by the device.
+func (in *Device) GetLEDBits() (LEDBits, error) {
+	bits, err := in.getBits(uint(eventcode.EV_LED), uint(keycode.LED_CNT))
+	return LEDBits{bits}, err
+}
+
+// GetSwitchStates returns the switches that are on.
+func (in *Device) GetSwitchStates() (SwitchBits, error) {
+	bits := keycode.NewBitField(uint(keycode.Switch_SW_CNT))
+	err := ioc.Ioctl(int(in.f.Fd()), EVIOCGSW(uint(len(bits.Data))), uintptr(unsafe.Pointer(&bits.Data[0])))
+	return SwitchBits{bits}, err
+}
+
+// AbsInfo is the state and range of an absolute axis.
+type AbsInfo struct {
+	Value      int32 `json:"value"`
+	Minimum    int32 `json:"minimum"`
+	Maximum    int32 `json:"maximum"`
+	Fuzz       int32 `json:"fuzz"`
+	Flat       int32 `json:"flat"`
+	Resolution int32 `json:"resolution"`
+}
+
+// GetAbsInfo returns the state and range of an absolute axis.
+func (in *Device) GetAbsInfo(axis keycode.Abs) (AbsInfo, error) {
+	var info AbsInfo
+	err := ioc.Ioctl(int(in.f.Fd()), EVIOCGABS(uint(axis)), uintptr(unsafe.Pointer(&info)))
+	return info, err
+}
+
+// Capabilities are the event types and codes supported by an input device.
+type Capabilities struct {
+	Types   []eventcode.EventType
+	Keys    *keycode.KeyBits
+	Rel     RelBits
+	Abs     AbsBits
+	AbsInfo map[keycode.Abs]AbsInfo
+	Switch  SwitchBits
+	Misc    MiscBits
+	Sound   SoundBits
+	LED     LEDBits
+	Props   []keycode.Prop
+}
+
+// HasType returns true if an event type is supported.
+func (c *Capabilities) HasType(t eventcode.EventType) bool {
+	for _, v := range c.Types {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCapabilities returns the event types and codes supported by the device, and the ranges of its absolute axes.
+func (in *Device) GetCapabilities() (*Capabilities, error) {
+	c := &Capabilities{Keys: &keycode.KeyBits{}, AbsInfo: map[keycode.Abs]AbsInfo{}}
+	var err error
+	if c.Types, err = in.GetEventTypes(); err != nil {
+		return nil, err
+	}
+	if c.HasType(eventcode.EV_KEY) {
+		if c.Keys, err = in.GetKeyBits(); err != nil {
+			return nil, err
+		}
+	}
+	if c.HasType(eventcode.EV_REL) {
+		if c.Rel, err = in.GetRelBits(); err != nil {
+			return nil, err
+		}
+	}
+	if c.HasType(eventcode.EV_ABS) {
+		if c.Abs, err = in.GetAbsBits(); err != nil {
+			return nil, err
+		}
+		for _, axis := range c.Abs.Codes() {
+			if c.AbsInfo[axis], err = in.GetAbsInfo(axis); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if c.HasType(eventcode.EV_SW) {
+		if c.Switch, err = in.GetSwitchBits(); err != nil {
+			return nil, err
+		}
+	}
+	if c.HasType(eventcode.EV_MSC) {
+		if c.Misc, err = in.GetMiscBits(); err != nil {
+			return nil, err
+		}
+	}
+	if c.HasType(eventcode.EV_SND) {
+		if c.Sound, err = in.GetSoundBits(); err != nil {
+			return nil, err
+		}
+	}
+	if c.HasType(eventcode.EV_LED) {
+		if c.LED, err = in.GetLEDBits(); err != nil {
+			return nil, err
+		}
+	}
+	if c.Props, err = in.GetProps(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -34,6 +34,7 @@ func (ev *InputEvent) ToBytes() []byte {
 
 type Device struct {
 	f       *os.File
+	path    string
 	grabbed bool
 }
 
@@ -42,7 +43,12 @@ func OpenDevice(device string) (*Device, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Device{f: f}, nil
+	return &Device{f: f, path: device}, nil
+}
+
+// Path returns the file path of the device node.
+func (in *Device) Path() string {
+	return in.path
 }
 
 func (in *Device) Close() error {
@@ -68,11 +74,14 @@ func (in *Device) ReadEvents(ctx context.Context) ([]InputEvent, error) {
 	if err != nil {
 		return nil, err
 	}
+	return UnmarshalEvents(buf[:bufSize])
+}
 
-	cnt := bufSize / EventSize
+// UnmarshalEvents converts the bytes read from an event device to input events.
+func UnmarshalEvents(b []byte) ([]InputEvent, error) {
+	cnt := len(b) / EventSize
 	events := make([]InputEvent, cnt)
 
-	b := buf[:bufSize]
 	for i := 0; len(b) >= EventSize; i++ {
 		n, err := events[i].unmarshal(b)
 		if err != nil {
@@ -92,6 +101,28 @@ func (in *Device) GetKeyBits() (*keycode.KeyBits, error) {
 	return &bits, nil
 }
 
+// GetEventTypes returns the event types supported by the device.
+func (in *Device) GetEventTypes() ([]eventcode.EventType, error) {
+	bits, err := in.getBits(0, uint(eventcode.EV_CNT))
+	if err != nil {
+		return nil, err
+	}
+	var types []eventcode.EventType
+	for i := uint(0); i < uint(eventcode.EV_CNT); i++ {
+		if bits.GetDefault(i, false) {
+			types = append(types, eventcode.EventType(i))
+		}
+	}
+	return types, nil
+}
+
+// getBits returns the event codes of an event type supported by the device, or the event types if ev is 0.
+func (in *Device) getBits(ev, cnt uint) (keycode.BitField, error) {
+	bits := keycode.NewBitField(cnt)
+	err := ioc.Ioctl(int(in.f.Fd()), EVIOCGBIT(ev, uint(len(bits.Data))), uintptr(unsafe.Pointer(&bits.Data[0])))
+	return bits, err
+}
+
 func (in *Device) GetKeyStates(bits []byte) error {
 	err := ioc.Ioctl(int(in.f.Fd()), EVIOCGKEY(uint(len(bits))), uintptr(unsafe.Pointer(&bits[0])))
 	if err != nil {
@@ -101,8 +132,95 @@ func (in *Device) GetKeyStates(bits []byte) error {
 }
 
 func (in *Device) GetName() (string, error) {
+	return in.getString(EVIOCGNAME)
+}
+
+// GetPhys returns the physical location of the device, e.g. "usb-0000:00:14.0-1/input0".
+func (in *Device) GetPhys() (string, error) {
+	return in.getString(EVIOCGPHYS)
+}
+
+// GetUniq returns the unique identifier of the device, e.g. a serial number or a Bluetooth address.
+func (in *Device) GetUniq() (string, error) {
+	return in.getString(EVIOCGUNIQ)
+}
+
+// GetVersion returns the version of the evdev driver.
+func (in *Device) GetVersion() (int, error) {
+	return unix.IoctlGetInt(int(in.f.Fd()), uint(EVIOCGVERSION))
+}
+
+// GetProps returns the device properties.
+func (in *Device) GetProps() ([]keycode.Prop, error) {
+	bits := keycode.NewBitField(uint(keycode.Prop_INPUT_PROP_CNT))
+	err := ioc.Ioctl(int(in.f.Fd()), EVIOCGPROP(uint(len(bits.Data))), uintptr(unsafe.Pointer(&bits.Data[0])))
+	if err != nil {
+		return nil, err
+	}
+	var props []keycode.Prop
+	for i := 0; i < int(keycode.Prop_INPUT_PROP_CNT); i++ {
+		if bits.GetDefault(uint(i), false) {
+			props = append(props, keycode.Prop(i))
+		}
+	}
+	return props, nil
+}
+
+// Info is the identity of an input device.
+type Info struct {
+	Path          string         `json:"path"`
+	Name          string         `json:"name"`
+	Phys          string         `json:"phys"`
+	Uniq          string         `json:"uniq"`
+	ID            InputID        `json:"id"`
+	DriverVersion int            `json:"driver_version"`
+	Props         []keycode.Prop `json:"props"`
+}
+
+// Info returns the identity of the device. Devices without a physical path or a unique identifier have empty strings.
+func (in *Device) Info() (*Info, error) {
+	info := &Info{Path: in.path}
+	var err error
+	if info.Name, err = in.GetName(); err != nil {
+		return nil, err
+	}
+	if info.Phys, err = in.GetPhys(); err != nil && err != unix.ENOENT {
+		return nil, err
+	}
+	if info.Uniq, err = in.GetUniq(); err != nil && err != unix.ENOENT {
+		return nil, err
+	}
+	if info.ID, err = in.GetID(); err != nil {
+		return nil, err
+	}
+	if info.DriverVersion, err = in.GetVersion(); err != nil {
+		return nil, err
+	}
+	if info.Props, err = in.GetProps(); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// String returns the name, IDs and path of the device for logging.
+func (info *Info) String() string {
+	return fmt.Sprintf("%s : %q %04x:%04x bus %v phys %q", info.Path, info.Name, info.ID.Vendor, info.ID.Product, keycode.Bus(info.ID.BusType), info.Phys)
+}
+
+// GetID returns the bus type, vendor, product and version of the device.
+func (in *Device) GetID() (InputID, error) {
+	var id InputID
+	err := ioc.Ioctl(int(in.f.Fd()), uint(EVIOCGID), uintptr(unsafe.Pointer(&id)))
+	if err != nil {
+		return InputID{}, err
+	}
+	return id, nil
+}
+
+// getString reads a NUL-terminated string with an ioctl request that takes the buffer length.
+func (in *Device) getString(req func(len uint) uint) (string, error) {
 	var buf [256]byte
-	err := ioc.Ioctl(int(in.f.Fd()), EVIOCGNAME(uint(len(buf))), uintptr(unsafe.Pointer(&buf[0])))
+	err := ioc.Ioctl(int(in.f.Fd()), req(uint(len(buf))), uintptr(unsafe.Pointer(&buf[0])))
 	if err != nil {
 		return "", err
 	}
@@ -170,70 +288,111 @@ func (in *Device) SetLED(code keycode.LED, on bool) error {
 }
 
 type InputID struct {
-	BusType uint16
-	Vendor  uint16
-	Product uint16
-	Version uint16
+	BusType uint16 `json:"bustype"`
+	Vendor  uint16 `json:"vendor"`
+	Product uint16 `json:"product"`
+	Version uint16 `json:"version"`
 }
 
-// OpenByName opens event devices in a directory that contains kbdName.
+// OpenByName opens the first event device in a directory with a name that contains kbdName.
+// An empty kbdName opens the keyboard with the highest capability score.
 func OpenByName(devDir string, kbdName string, verbosity int) (*Device, error) {
-	files, err := ioutil.ReadDir(devDir)
+	devs, errList, err := OpenAll(devDir)
 	if err != nil {
 		return nil, err
 	}
+	dev := SelectByName(devs, kbdName)
+	if dev == nil {
+		closeAll(devs, nil, verbosity)
+		return nil, noDeviceError(errList)
+	}
+	closeAll(devs, []*Device{dev}, verbosity)
+	return dev, nil
+}
 
-	var file string
-	var name string
+// SelectByName returns the first device with a name that contains kbdName, or nil if there is none.
+// An empty kbdName selects the keyboard with the highest capability score.
+func SelectByName(devs []*Device, kbdName string) *Device {
+	m := NameMatcher(kbdName)
 	var dev *Device
-	var errList []error
+	var score int
+	for _, d := range devs {
+		if !m.Match(d) {
+			continue
+		}
+		if kbdName != "" {
+			return d
+		}
+		if s := d.KeyboardScore(); dev == nil || s > score {
+			dev, score = d, s
+		}
+	}
+	return dev
+}
 
+// OpenAll opens all event devices in a directory in the order of their file names.
+// It returns the devices that could be opened and the errors of the others.
+func OpenAll(devDir string) ([]*Device, []error, error) {
+	files, err := ioutil.ReadDir(devDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	var devs []*Device
+	var errList []error
 	for _, v := range files {
 		if !strings.HasPrefix(v.Name(), "event") {
 			continue
 		}
-		file = filepath.Join(devDir, v.Name())
-		d, err := OpenDevice(file)
+		d, err := OpenDevice(filepath.Join(devDir, v.Name()))
 		if err != nil {
 			errList = append(errList, err)
 			continue
 		}
-		// defer d.Close()
-		name, err = d.GetName()
-		if err != nil {
-			errList = append(errList, err)
-			continue
+		devs = append(devs, d)
+	}
+	return devs, errList, nil
+}
+
+// closeAll closes the devices that are not kept and logs which devices are kept.
+func closeAll(devs, keep []*Device, verbosity int) {
+	for _, d := range devs {
+		kept := false
+		for _, k := range keep {
+			kept = kept || k == d
 		}
-		if kbdName != "" {
-			// Match by name
-			if strings.Contains(name, kbdName) {
-				dev = d
-				d = nil
-				break
+		if verbosity > 1 {
+			desc := d.Path()
+			if info, err := d.Info(); err == nil {
+				desc = info.String()
+			}
+			if kept {
+				log.Infof("Opened keyboard input device: %v", desc)
+			} else {
+				log.Infof("Skipped input device: %v", desc)
 			}
-		} else if d.IsKeyboard() {
-			// Or use first keyboard device
-			dev = d
-			d = nil
-			break
 		}
-		if verbosity > 1 {
-			log.Infof("Skipped non-keyboard input device: %v : %v", file, name)
+		if kept {
+			continue
 		}
 		if err := d.Close(); err != nil {
 			log.Errorf("failed to close an evdev device: %v", err)
 		}
 	}
+}
 
-	if dev == nil {
-		if len(errList) == 0 {
-			return nil, errors.New("found no input device")
-		}
-		return nil, fmt.Errorf("found no input device: %v", errList)
+// noDeviceError returns the error of finding no input device, with the errors of the devices that could not be opened.
+func noDeviceError(errList []error) error {
+	if len(errList) == 0 {
+		return errors.New("found no input device")
 	}
+	return fmt.Errorf("found no input device: %v", errList)
+}
 
-	if verbosity > 1 {
-		log.Infof("Opened keyboard input device: %v : %v", file, name)
+// OpenAllByName opens all event devices in a directory with a name that contains any of kbdNames.
+func OpenAllByName(devDir string, kbdNames []string, verbosity int) ([]*Device, error) {
+	var ms []*Matcher
+	for _, n := range kbdNames {
+		ms = append(ms, NameMatcher(n))
 	}
-	return dev, nil
+	return OpenMatching(devDir, ms, verbosity)
 }
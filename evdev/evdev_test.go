@@ -1,5 +1,61 @@
 package evdev
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/erdichen/chromekey/evdev/keycode"
+)
 
 func TestEvdev(t *testing.T) {}
+
+func TestParseID(t *testing.T) {
+	v, p, err := ParseID("17ef:6047")
+	if err != nil || v != 0x17ef || p != 0x6047 {
+		t.Errorf("ParseID: got %x:%x %v want 17ef:6047", v, p, err)
+	}
+	for _, s := range []string{"", "17ef", "17ef:", "17ef:6047:1", "xyz:6047", "17ef:10000"} {
+		if _, _, err := ParseID(s); err == nil {
+			t.Errorf("ParseID(%q): got no error", s)
+		}
+	}
+}
+
+func TestBits(t *testing.T) {
+	bits := keycode.NewBitField(uint(keycode.Switch_SW_CNT))
+	bits.Set(uint(keycode.Switch_SW_LID), true)
+	bits.Set(uint(keycode.Switch_SW_TABLET_MODE), true)
+	sw := SwitchBits{bits}
+	if !sw.Has(keycode.Switch_SW_TABLET_MODE) || sw.Has(keycode.Switch_SW_DOCK) {
+		t.Errorf("Has: got %v", sw.Codes())
+	}
+	if got := sw.Codes(); len(got) != 2 || got[0] != keycode.Switch_SW_LID || got[1] != keycode.Switch_SW_TABLET_MODE {
+		t.Errorf("Codes: got %v want [SW_LID SW_TABLET_MODE]", got)
+	}
+	// A device without the event type has an empty set.
+	if got := (RelBits{}).Codes(); len(got) != 0 {
+		t.Errorf("empty Codes: got %v", got)
+	}
+}
+
+func TestRemapDevices(t *testing.T) {
+	id := InputID{BusType: 3, Vendor: 1, Product: 1, Version: 1}
+	AddRemapDevice("test keyboard", "remap", id)
+	AddRemapDevice("test keyboard", "remap", id)
+	RemoveRemapDevice("test keyboard", "remap", id)
+	if !hasRemapName("test keyboard") {
+		t.Errorf("hasRemapName: got false after removing one of two devices")
+	}
+	RemoveRemapDevice("test keyboard", "remap", id)
+	if hasRemapName("test keyboard") {
+		t.Errorf("hasRemapName: got true after removing all devices")
+	}
+}
+
+func TestNameMatcher(t *testing.T) {
+	if m := NameMatcher("ThinkPad"); m.Name != "ThinkPad" || !m.Virtual || m.MinScore != 0 {
+		t.Errorf("NameMatcher: got %+v want a name that also matches virtual keyboards", m)
+	}
+	if m := NameMatcher(""); m.Virtual || m.MinScore != MinKeyboardScore {
+		t.Errorf("NameMatcher: got %+v want a keyboard score that skips virtual keyboards", m)
+	}
+}
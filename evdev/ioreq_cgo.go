@@ -20,6 +20,26 @@ unsigned int _EVIOCGLED(unsigned int len) {
 unsigned int _EVIOCGNAME(unsigned int len) {
 	return EVIOCGNAME(len);
 }
+
+unsigned int _EVIOCGPHYS(unsigned int len) {
+	return EVIOCGPHYS(len);
+}
+
+unsigned int _EVIOCGSW(unsigned int len) {
+	return EVIOCGSW(len);
+}
+
+unsigned int _EVIOCGABS(unsigned int abs) {
+	return EVIOCGABS(abs);
+}
+
+unsigned int _EVIOCGUNIQ(unsigned int len) {
+	return EVIOCGUNIQ(len);
+}
+
+unsigned int _EVIOCGPROP(unsigned int len) {
+	return EVIOCGPROP(len);
+}
 */
 import "C"
 
@@ -29,6 +49,8 @@ import (
 )
 
 var (
+	EVIOCGVERSION = C.EVIOCGVERSION
+	EVIOCGID      = C.EVIOCGID
 	EVIOCGRAB     = C.EVIOCGRAB
 	EVIOCREVOKE   = C.EVIOCREVOKE
 	EVIOCSCLOCKID = C.EVIOCSCLOCKID
@@ -50,6 +72,26 @@ func EVIOCGNAME(len uint) uint {
 	return uint(C._EVIOCGNAME(C.uint(len)))
 }
 
+func EVIOCGPHYS(len uint) uint {
+	return uint(C._EVIOCGPHYS(C.uint(len)))
+}
+
+func EVIOCGUNIQ(len uint) uint {
+	return uint(C._EVIOCGUNIQ(C.uint(len)))
+}
+
+func EVIOCGPROP(len uint) uint {
+	return uint(C._EVIOCGPROP(C.uint(len)))
+}
+
+func EVIOCGSW(len uint) uint {
+	return uint(C._EVIOCGSW(C.uint(len)))
+}
+
+func EVIOCGABS(abs uint) uint {
+	return uint(C._EVIOCGABS(C.uint(abs)))
+}
+
 func structSizeMismatch()
 
 const EventSize = int(unsafe.Sizeof(*(*C.struct_input_event)(nil)))
@@ -59,6 +101,9 @@ func init() {
 	if goEventSize != EventSize {
 		structSizeMismatch()
 	}
+	if unsafe.Sizeof(AbsInfo{}) != unsafe.Sizeof(*(*C.struct_input_absinfo)(nil)) {
+		structSizeMismatch()
+	}
 }
 
 func (ev *InputEvent) Marshal() []byte {
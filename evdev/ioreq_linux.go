@@ -11,6 +11,8 @@ import (
 )
 
 var (
+	EVIOCGVERSION = ioc.IOR('E', 0x01, 4)
+	EVIOCGID      = ioc.IOR('E', 0x02, 8)
 	EVIOCGRAB     = ioc.IOW('E', 0x90, 4)
 	EVIOCREVOKE   = ioc.IOW('E', 0x91, 4)
 	EVIOCSCLOCKID = ioc.IOW('E', 0xa0, 4)
@@ -32,6 +34,26 @@ func EVIOCGNAME(len uint) uint {
 	return ioc.IOC(ioc.Read, 'E', 0x06, len)
 }
 
+func EVIOCGPHYS(len uint) uint {
+	return ioc.IOC(ioc.Read, 'E', 0x07, len)
+}
+
+func EVIOCGSW(len uint) uint {
+	return ioc.IOC(ioc.Read, 'E', 0x1b, len)
+}
+
+func EVIOCGABS(abs uint) uint {
+	return ioc.IOR('E', 0x40+abs, 24)
+}
+
+func EVIOCGUNIQ(len uint) uint {
+	return ioc.IOC(ioc.Read, 'E', 0x08, len)
+}
+
+func EVIOCGPROP(len uint) uint {
+	return ioc.IOC(ioc.Read, 'E', 0x09, len)
+}
+
 const EventSize = int(unsafe.Sizeof(*(*InputEvent)(nil)))
 
 func (ev *InputEvent) Marshal() []byte {
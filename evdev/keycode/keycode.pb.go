@@ -2027,6 +2027,603 @@ func (LED) EnumDescriptor() ([]byte, []int) {
 	return file_keycode_proto_rawDescGZIP(), []int{1}
 }
 
+// IDs.
+type Bus int32
+
+const (
+	Bus_BUS_NONE        Bus = 0
+	Bus_BUS_PCI         Bus = 1
+	Bus_BUS_ISAPNP      Bus = 2
+	Bus_BUS_USB         Bus = 3
+	Bus_BUS_HIL         Bus = 4
+	Bus_BUS_BLUETOOTH   Bus = 5
+	Bus_BUS_VIRTUAL     Bus = 6
+	Bus_BUS_ISA         Bus = 16
+	Bus_BUS_I8042       Bus = 17
+	Bus_BUS_XTKBD       Bus = 18
+	Bus_BUS_RS232       Bus = 19
+	Bus_BUS_GAMEPORT    Bus = 20
+	Bus_BUS_PARPORT     Bus = 21
+	Bus_BUS_AMIGA       Bus = 22
+	Bus_BUS_ADB         Bus = 23
+	Bus_BUS_I2C         Bus = 24
+	Bus_BUS_HOST        Bus = 25
+	Bus_BUS_GSC         Bus = 26
+	Bus_BUS_ATARI       Bus = 27
+	Bus_BUS_SPI         Bus = 28
+	Bus_BUS_RMI         Bus = 29
+	Bus_BUS_CEC         Bus = 30
+	Bus_BUS_INTEL_ISHTP Bus = 31
+)
+
+// Enum value maps for Bus.
+var (
+	Bus_name = map[int32]string{
+		0:  "BUS_NONE",
+		1:  "BUS_PCI",
+		2:  "BUS_ISAPNP",
+		3:  "BUS_USB",
+		4:  "BUS_HIL",
+		5:  "BUS_BLUETOOTH",
+		6:  "BUS_VIRTUAL",
+		16: "BUS_ISA",
+		17: "BUS_I8042",
+		18: "BUS_XTKBD",
+		19: "BUS_RS232",
+		20: "BUS_GAMEPORT",
+		21: "BUS_PARPORT",
+		22: "BUS_AMIGA",
+		23: "BUS_ADB",
+		24: "BUS_I2C",
+		25: "BUS_HOST",
+		26: "BUS_GSC",
+		27: "BUS_ATARI",
+		28: "BUS_SPI",
+		29: "BUS_RMI",
+		30: "BUS_CEC",
+		31: "BUS_INTEL_ISHTP",
+	}
+	Bus_value = map[string]int32{
+		"BUS_NONE":        0,
+		"BUS_PCI":         1,
+		"BUS_ISAPNP":      2,
+		"BUS_USB":         3,
+		"BUS_HIL":         4,
+		"BUS_BLUETOOTH":   5,
+		"BUS_VIRTUAL":     6,
+		"BUS_ISA":         16,
+		"BUS_I8042":       17,
+		"BUS_XTKBD":       18,
+		"BUS_RS232":       19,
+		"BUS_GAMEPORT":    20,
+		"BUS_PARPORT":     21,
+		"BUS_AMIGA":       22,
+		"BUS_ADB":         23,
+		"BUS_I2C":         24,
+		"BUS_HOST":        25,
+		"BUS_GSC":         26,
+		"BUS_ATARI":       27,
+		"BUS_SPI":         28,
+		"BUS_RMI":         29,
+		"BUS_CEC":         30,
+		"BUS_INTEL_ISHTP": 31,
+	}
+)
+
+func (x Bus) Enum() *Bus {
+	p := new(Bus)
+	*p = x
+	return p
+}
+
+func (x Bus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Bus) Descriptor() protoreflect.EnumDescriptor {
+	return file_keycode_proto_enumTypes[2].Descriptor()
+}
+
+func (Bus) Type() protoreflect.EnumType {
+	return &file_keycode_proto_enumTypes[2]
+}
+
+func (x Bus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Bus.Descriptor instead.
+func (Bus) EnumDescriptor() ([]byte, []int) {
+	return file_keycode_proto_rawDescGZIP(), []int{2}
+}
+
+// Device properties and quirks.
+type Prop int32
+
+const (
+	Prop_INPUT_PROP_POINTER        Prop = 0
+	Prop_INPUT_PROP_DIRECT         Prop = 1
+	Prop_INPUT_PROP_BUTTONPAD      Prop = 2
+	Prop_INPUT_PROP_SEMI_MT        Prop = 3
+	Prop_INPUT_PROP_TOPBUTTONPAD   Prop = 4
+	Prop_INPUT_PROP_POINTING_STICK Prop = 5
+	Prop_INPUT_PROP_ACCELEROMETER  Prop = 6
+	Prop_INPUT_PROP_MAX            Prop = 31
+	Prop_INPUT_PROP_CNT            Prop = 32
+)
+
+// Enum value maps for Prop.
+var (
+	Prop_name = map[int32]string{
+		0:  "INPUT_PROP_POINTER",
+		1:  "INPUT_PROP_DIRECT",
+		2:  "INPUT_PROP_BUTTONPAD",
+		3:  "INPUT_PROP_SEMI_MT",
+		4:  "INPUT_PROP_TOPBUTTONPAD",
+		5:  "INPUT_PROP_POINTING_STICK",
+		6:  "INPUT_PROP_ACCELEROMETER",
+		31: "INPUT_PROP_MAX",
+		32: "INPUT_PROP_CNT",
+	}
+	Prop_value = map[string]int32{
+		"INPUT_PROP_POINTER":        0,
+		"INPUT_PROP_DIRECT":         1,
+		"INPUT_PROP_BUTTONPAD":      2,
+		"INPUT_PROP_SEMI_MT":        3,
+		"INPUT_PROP_TOPBUTTONPAD":   4,
+		"INPUT_PROP_POINTING_STICK": 5,
+		"INPUT_PROP_ACCELEROMETER":  6,
+		"INPUT_PROP_MAX":            31,
+		"INPUT_PROP_CNT":            32,
+	}
+)
+
+func (x Prop) Enum() *Prop {
+	p := new(Prop)
+	*p = x
+	return p
+}
+
+func (x Prop) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Prop) Descriptor() protoreflect.EnumDescriptor {
+	return file_keycode_proto_enumTypes[3].Descriptor()
+}
+
+func (Prop) Type() protoreflect.EnumType {
+	return &file_keycode_proto_enumTypes[3]
+}
+
+func (x Prop) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Prop.Descriptor instead.
+func (Prop) EnumDescriptor() ([]byte, []int) {
+	return file_keycode_proto_rawDescGZIP(), []int{3}
+}
+
+// Relative axes
+type Rel int32
+
+const (
+	Rel_REL_X             Rel = 0
+	Rel_REL_Y             Rel = 1
+	Rel_REL_Z             Rel = 2
+	Rel_REL_RX            Rel = 3
+	Rel_REL_RY            Rel = 4
+	Rel_REL_RZ            Rel = 5
+	Rel_REL_HWHEEL        Rel = 6
+	Rel_REL_DIAL          Rel = 7
+	Rel_REL_WHEEL         Rel = 8
+	Rel_REL_MISC          Rel = 9
+	Rel_REL_RESERVED      Rel = 10
+	Rel_REL_WHEEL_HI_RES  Rel = 11
+	Rel_REL_HWHEEL_HI_RES Rel = 12
+	Rel_REL_MAX           Rel = 15
+	Rel_REL_CNT           Rel = 16
+)
+
+// Enum value maps for Rel.
+var (
+	Rel_name = map[int32]string{
+		0:  "REL_X",
+		1:  "REL_Y",
+		2:  "REL_Z",
+		3:  "REL_RX",
+		4:  "REL_RY",
+		5:  "REL_RZ",
+		6:  "REL_HWHEEL",
+		7:  "REL_DIAL",
+		8:  "REL_WHEEL",
+		9:  "REL_MISC",
+		10: "REL_RESERVED",
+		11: "REL_WHEEL_HI_RES",
+		12: "REL_HWHEEL_HI_RES",
+		15: "REL_MAX",
+		16: "REL_CNT",
+	}
+	Rel_value = map[string]int32{
+		"REL_X":             0,
+		"REL_Y":             1,
+		"REL_Z":             2,
+		"REL_RX":            3,
+		"REL_RY":            4,
+		"REL_RZ":            5,
+		"REL_HWHEEL":        6,
+		"REL_DIAL":          7,
+		"REL_WHEEL":         8,
+		"REL_MISC":          9,
+		"REL_RESERVED":      10,
+		"REL_WHEEL_HI_RES":  11,
+		"REL_HWHEEL_HI_RES": 12,
+		"REL_MAX":           15,
+		"REL_CNT":           16,
+	}
+)
+
+func (x Rel) Enum() *Rel {
+	p := new(Rel)
+	*p = x
+	return p
+}
+
+func (x Rel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Rel) Descriptor() protoreflect.EnumDescriptor {
+	return file_keycode_proto_enumTypes[4].Descriptor()
+}
+
+func (Rel) Type() protoreflect.EnumType {
+	return &file_keycode_proto_enumTypes[4]
+}
+
+func (x Rel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Rel.Descriptor instead.
+func (Rel) EnumDescriptor() ([]byte, []int) {
+	return file_keycode_proto_rawDescGZIP(), []int{4}
+}
+
+// Absolute axes
+type Abs int32
+
+const (
+	Abs_ABS_X              Abs = 0
+	Abs_ABS_Y              Abs = 1
+	Abs_ABS_Z              Abs = 2
+	Abs_ABS_RX             Abs = 3
+	Abs_ABS_RY             Abs = 4
+	Abs_ABS_RZ             Abs = 5
+	Abs_ABS_THROTTLE       Abs = 6
+	Abs_ABS_RUDDER         Abs = 7
+	Abs_ABS_WHEEL          Abs = 8
+	Abs_ABS_GAS            Abs = 9
+	Abs_ABS_BRAKE          Abs = 10
+	Abs_ABS_HAT0X          Abs = 16
+	Abs_ABS_HAT0Y          Abs = 17
+	Abs_ABS_HAT1X          Abs = 18
+	Abs_ABS_HAT1Y          Abs = 19
+	Abs_ABS_HAT2X          Abs = 20
+	Abs_ABS_HAT2Y          Abs = 21
+	Abs_ABS_HAT3X          Abs = 22
+	Abs_ABS_HAT3Y          Abs = 23
+	Abs_ABS_PRESSURE       Abs = 24
+	Abs_ABS_DISTANCE       Abs = 25
+	Abs_ABS_TILT_X         Abs = 26
+	Abs_ABS_TILT_Y         Abs = 27
+	Abs_ABS_TOOL_WIDTH     Abs = 28
+	Abs_ABS_VOLUME         Abs = 32
+	Abs_ABS_PROFILE        Abs = 33
+	Abs_ABS_MISC           Abs = 40
+	Abs_ABS_RESERVED       Abs = 46
+	Abs_ABS_MT_SLOT        Abs = 47
+	Abs_ABS_MT_TOUCH_MAJOR Abs = 48
+	Abs_ABS_MT_TOUCH_MINOR Abs = 49
+	Abs_ABS_MT_WIDTH_MAJOR Abs = 50
+	Abs_ABS_MT_WIDTH_MINOR Abs = 51
+	Abs_ABS_MT_ORIENTATION Abs = 52
+	Abs_ABS_MT_POSITION_X  Abs = 53
+	Abs_ABS_MT_POSITION_Y  Abs = 54
+	Abs_ABS_MT_TOOL_TYPE   Abs = 55
+	Abs_ABS_MT_BLOB_ID     Abs = 56
+	Abs_ABS_MT_TRACKING_ID Abs = 57
+	Abs_ABS_MT_PRESSURE    Abs = 58
+	Abs_ABS_MT_DISTANCE    Abs = 59
+	Abs_ABS_MT_TOOL_X      Abs = 60
+	Abs_ABS_MT_TOOL_Y      Abs = 61
+	Abs_ABS_MAX            Abs = 63
+	Abs_ABS_CNT            Abs = 64
+)
+
+// Enum value maps for Abs.
+var (
+	Abs_name = map[int32]string{
+		0:  "ABS_X",
+		1:  "ABS_Y",
+		2:  "ABS_Z",
+		3:  "ABS_RX",
+		4:  "ABS_RY",
+		5:  "ABS_RZ",
+		6:  "ABS_THROTTLE",
+		7:  "ABS_RUDDER",
+		8:  "ABS_WHEEL",
+		9:  "ABS_GAS",
+		10: "ABS_BRAKE",
+		16: "ABS_HAT0X",
+		17: "ABS_HAT0Y",
+		18: "ABS_HAT1X",
+		19: "ABS_HAT1Y",
+		20: "ABS_HAT2X",
+		21: "ABS_HAT2Y",
+		22: "ABS_HAT3X",
+		23: "ABS_HAT3Y",
+		24: "ABS_PRESSURE",
+		25: "ABS_DISTANCE",
+		26: "ABS_TILT_X",
+		27: "ABS_TILT_Y",
+		28: "ABS_TOOL_WIDTH",
+		32: "ABS_VOLUME",
+		33: "ABS_PROFILE",
+		40: "ABS_MISC",
+		46: "ABS_RESERVED",
+		47: "ABS_MT_SLOT",
+		48: "ABS_MT_TOUCH_MAJOR",
+		49: "ABS_MT_TOUCH_MINOR",
+		50: "ABS_MT_WIDTH_MAJOR",
+		51: "ABS_MT_WIDTH_MINOR",
+		52: "ABS_MT_ORIENTATION",
+		53: "ABS_MT_POSITION_X",
+		54: "ABS_MT_POSITION_Y",
+		55: "ABS_MT_TOOL_TYPE",
+		56: "ABS_MT_BLOB_ID",
+		57: "ABS_MT_TRACKING_ID",
+		58: "ABS_MT_PRESSURE",
+		59: "ABS_MT_DISTANCE",
+		60: "ABS_MT_TOOL_X",
+		61: "ABS_MT_TOOL_Y",
+		63: "ABS_MAX",
+		64: "ABS_CNT",
+	}
+	Abs_value = map[string]int32{
+		"ABS_X":              0,
+		"ABS_Y":              1,
+		"ABS_Z":              2,
+		"ABS_RX":             3,
+		"ABS_RY":             4,
+		"ABS_RZ":             5,
+		"ABS_THROTTLE":       6,
+		"ABS_RUDDER":         7,
+		"ABS_WHEEL":          8,
+		"ABS_GAS":            9,
+		"ABS_BRAKE":          10,
+		"ABS_HAT0X":          16,
+		"ABS_HAT0Y":          17,
+		"ABS_HAT1X":          18,
+		"ABS_HAT1Y":          19,
+		"ABS_HAT2X":          20,
+		"ABS_HAT2Y":          21,
+		"ABS_HAT3X":          22,
+		"ABS_HAT3Y":          23,
+		"ABS_PRESSURE":       24,
+		"ABS_DISTANCE":       25,
+		"ABS_TILT_X":         26,
+		"ABS_TILT_Y":         27,
+		"ABS_TOOL_WIDTH":     28,
+		"ABS_VOLUME":         32,
+		"ABS_PROFILE":        33,
+		"ABS_MISC":           40,
+		"ABS_RESERVED":       46,
+		"ABS_MT_SLOT":        47,
+		"ABS_MT_TOUCH_MAJOR": 48,
+		"ABS_MT_TOUCH_MINOR": 49,
+		"ABS_MT_WIDTH_MAJOR": 50,
+		"ABS_MT_WIDTH_MINOR": 51,
+		"ABS_MT_ORIENTATION": 52,
+		"ABS_MT_POSITION_X":  53,
+		"ABS_MT_POSITION_Y":  54,
+		"ABS_MT_TOOL_TYPE":   55,
+		"ABS_MT_BLOB_ID":     56,
+		"ABS_MT_TRACKING_ID": 57,
+		"ABS_MT_PRESSURE":    58,
+		"ABS_MT_DISTANCE":    59,
+		"ABS_MT_TOOL_X":      60,
+		"ABS_MT_TOOL_Y":      61,
+		"ABS_MAX":            63,
+		"ABS_CNT":            64,
+	}
+)
+
+func (x Abs) Enum() *Abs {
+	p := new(Abs)
+	*p = x
+	return p
+}
+
+func (x Abs) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Abs) Descriptor() protoreflect.EnumDescriptor {
+	return file_keycode_proto_enumTypes[5].Descriptor()
+}
+
+func (Abs) Type() protoreflect.EnumType {
+	return &file_keycode_proto_enumTypes[5]
+}
+
+func (x Abs) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Abs.Descriptor instead.
+func (Abs) EnumDescriptor() ([]byte, []int) {
+	return file_keycode_proto_rawDescGZIP(), []int{5}
+}
+
+// Switch events
+type Switch int32
+
+const (
+	Switch_SW_LID                  Switch = 0
+	Switch_SW_TABLET_MODE          Switch = 1
+	Switch_SW_HEADPHONE_INSERT     Switch = 2
+	Switch_SW_RFKILL_ALL           Switch = 3
+	Switch_SW_RADIO                Switch = 3
+	Switch_SW_MICROPHONE_INSERT    Switch = 4
+	Switch_SW_DOCK                 Switch = 5
+	Switch_SW_LINEOUT_INSERT       Switch = 6
+	Switch_SW_JACK_PHYSICAL_INSERT Switch = 7
+	Switch_SW_VIDEOOUT_INSERT      Switch = 8
+	Switch_SW_CAMERA_LENS_COVER    Switch = 9
+	Switch_SW_KEYPAD_SLIDE         Switch = 10
+	Switch_SW_FRONT_PROXIMITY      Switch = 11
+	Switch_SW_ROTATE_LOCK          Switch = 12
+	Switch_SW_LINEIN_INSERT        Switch = 13
+	Switch_SW_MUTE_DEVICE          Switch = 14
+	Switch_SW_PEN_INSERTED         Switch = 15
+	Switch_SW_MACHINE_COVER        Switch = 16
+	Switch_SW_MAX                  Switch = 16
+	Switch_SW_CNT                  Switch = 17
+)
+
+// Enum value maps for Switch.
+var (
+	Switch_name = map[int32]string{
+		0: "SW_LID",
+		1: "SW_TABLET_MODE",
+		2: "SW_HEADPHONE_INSERT",
+		3: "SW_RFKILL_ALL",
+		// Duplicate value: 3: "SW_RADIO",
+		4:  "SW_MICROPHONE_INSERT",
+		5:  "SW_DOCK",
+		6:  "SW_LINEOUT_INSERT",
+		7:  "SW_JACK_PHYSICAL_INSERT",
+		8:  "SW_VIDEOOUT_INSERT",
+		9:  "SW_CAMERA_LENS_COVER",
+		10: "SW_KEYPAD_SLIDE",
+		11: "SW_FRONT_PROXIMITY",
+		12: "SW_ROTATE_LOCK",
+		13: "SW_LINEIN_INSERT",
+		14: "SW_MUTE_DEVICE",
+		15: "SW_PEN_INSERTED",
+		16: "SW_MACHINE_COVER",
+		// Duplicate value: 16: "SW_MAX",
+		17: "SW_CNT",
+	}
+	Switch_value = map[string]int32{
+		"SW_LID":                  0,
+		"SW_TABLET_MODE":          1,
+		"SW_HEADPHONE_INSERT":     2,
+		"SW_RFKILL_ALL":           3,
+		"SW_RADIO":                3,
+		"SW_MICROPHONE_INSERT":    4,
+		"SW_DOCK":                 5,
+		"SW_LINEOUT_INSERT":       6,
+		"SW_JACK_PHYSICAL_INSERT": 7,
+		"SW_VIDEOOUT_INSERT":      8,
+		"SW_CAMERA_LENS_COVER":    9,
+		"SW_KEYPAD_SLIDE":         10,
+		"SW_FRONT_PROXIMITY":      11,
+		"SW_ROTATE_LOCK":          12,
+		"SW_LINEIN_INSERT":        13,
+		"SW_MUTE_DEVICE":          14,
+		"SW_PEN_INSERTED":         15,
+		"SW_MACHINE_COVER":        16,
+		"SW_MAX":                  16,
+		"SW_CNT":                  17,
+	}
+)
+
+func (x Switch) Enum() *Switch {
+	p := new(Switch)
+	*p = x
+	return p
+}
+
+func (x Switch) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Switch) Descriptor() protoreflect.EnumDescriptor {
+	return file_keycode_proto_enumTypes[6].Descriptor()
+}
+
+func (Switch) Type() protoreflect.EnumType {
+	return &file_keycode_proto_enumTypes[6]
+}
+
+func (x Switch) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Switch.Descriptor instead.
+func (Switch) EnumDescriptor() ([]byte, []int) {
+	return file_keycode_proto_rawDescGZIP(), []int{6}
+}
+
+// Sounds
+type Sound int32
+
+const (
+	Sound_SND_CLICK Sound = 0
+	Sound_SND_BELL  Sound = 1
+	Sound_SND_TONE  Sound = 2
+	Sound_SND_MAX   Sound = 7
+	Sound_SND_CNT   Sound = 8
+)
+
+// Enum value maps for Sound.
+var (
+	Sound_name = map[int32]string{
+		0: "SND_CLICK",
+		1: "SND_BELL",
+		2: "SND_TONE",
+		7: "SND_MAX",
+		8: "SND_CNT",
+	}
+	Sound_value = map[string]int32{
+		"SND_CLICK": 0,
+		"SND_BELL":  1,
+		"SND_TONE":  2,
+		"SND_MAX":   7,
+		"SND_CNT":   8,
+	}
+)
+
+func (x Sound) Enum() *Sound {
+	p := new(Sound)
+	*p = x
+	return p
+}
+
+func (x Sound) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Sound) Descriptor() protoreflect.EnumDescriptor {
+	return file_keycode_proto_enumTypes[7].Descriptor()
+}
+
+func (Sound) Type() protoreflect.EnumType {
+	return &file_keycode_proto_enumTypes[7]
+}
+
+func (x Sound) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Sound.Descriptor instead.
+func (Sound) EnumDescriptor() ([]byte, []int) {
+	return file_keycode_proto_rawDescGZIP(), []int{7}
+}
+
 var File_keycode_proto protoreflect.FileDescriptor
 
 var file_keycode_proto_rawDesc = []byte{
@@ -2737,10 +3334,141 @@ var file_keycode_proto_rawDesc = []byte{
 	0x54, 0x45, 0x10, 0x07, 0x12, 0x08, 0x0a, 0x04, 0x4d, 0x49, 0x53, 0x43, 0x10, 0x08, 0x12, 0x08,
 	0x0a, 0x04, 0x4d, 0x41, 0x49, 0x4c, 0x10, 0x09, 0x12, 0x0c, 0x0a, 0x08, 0x43, 0x48, 0x41, 0x52,
 	0x47, 0x49, 0x4e, 0x47, 0x10, 0x0a, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x41, 0x58, 0x10, 0x0f, 0x12,
-	0x07, 0x0a, 0x03, 0x43, 0x4e, 0x54, 0x10, 0x10, 0x42, 0x2d, 0x5a, 0x2b, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x72, 0x64, 0x69, 0x63, 0x68, 0x65, 0x6e, 0x2f,
-	0x63, 0x68, 0x72, 0x6f, 0x6d, 0x65, 0x6b, 0x65, 0x79, 0x2f, 0x65, 0x76, 0x64, 0x65, 0x76, 0x2f,
-	0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x07, 0x0a, 0x03, 0x43, 0x4e, 0x54, 0x10, 0x10, 0x2a, 0xda, 0x02, 0x0a, 0x03, 0x42, 0x75, 0x73,
+	0x12, 0x0c, 0x0a, 0x08, 0x42, 0x55, 0x53, 0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x0b,
+	0x0a, 0x07, 0x42, 0x55, 0x53, 0x5f, 0x50, 0x43, 0x49, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x42,
+	0x55, 0x53, 0x5f, 0x49, 0x53, 0x41, 0x50, 0x4e, 0x50, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x42,
+	0x55, 0x53, 0x5f, 0x55, 0x53, 0x42, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x42, 0x55, 0x53, 0x5f,
+	0x48, 0x49, 0x4c, 0x10, 0x04, 0x12, 0x11, 0x0a, 0x0d, 0x42, 0x55, 0x53, 0x5f, 0x42, 0x4c, 0x55,
+	0x45, 0x54, 0x4f, 0x4f, 0x54, 0x48, 0x10, 0x05, 0x12, 0x0f, 0x0a, 0x0b, 0x42, 0x55, 0x53, 0x5f,
+	0x56, 0x49, 0x52, 0x54, 0x55, 0x41, 0x4c, 0x10, 0x06, 0x12, 0x0b, 0x0a, 0x07, 0x42, 0x55, 0x53,
+	0x5f, 0x49, 0x53, 0x41, 0x10, 0x10, 0x12, 0x0d, 0x0a, 0x09, 0x42, 0x55, 0x53, 0x5f, 0x49, 0x38,
+	0x30, 0x34, 0x32, 0x10, 0x11, 0x12, 0x0d, 0x0a, 0x09, 0x42, 0x55, 0x53, 0x5f, 0x58, 0x54, 0x4b,
+	0x42, 0x44, 0x10, 0x12, 0x12, 0x0d, 0x0a, 0x09, 0x42, 0x55, 0x53, 0x5f, 0x52, 0x53, 0x32, 0x33,
+	0x32, 0x10, 0x13, 0x12, 0x10, 0x0a, 0x0c, 0x42, 0x55, 0x53, 0x5f, 0x47, 0x41, 0x4d, 0x45, 0x50,
+	0x4f, 0x52, 0x54, 0x10, 0x14, 0x12, 0x0f, 0x0a, 0x0b, 0x42, 0x55, 0x53, 0x5f, 0x50, 0x41, 0x52,
+	0x50, 0x4f, 0x52, 0x54, 0x10, 0x15, 0x12, 0x0d, 0x0a, 0x09, 0x42, 0x55, 0x53, 0x5f, 0x41, 0x4d,
+	0x49, 0x47, 0x41, 0x10, 0x16, 0x12, 0x0b, 0x0a, 0x07, 0x42, 0x55, 0x53, 0x5f, 0x41, 0x44, 0x42,
+	0x10, 0x17, 0x12, 0x0b, 0x0a, 0x07, 0x42, 0x55, 0x53, 0x5f, 0x49, 0x32, 0x43, 0x10, 0x18, 0x12,
+	0x0c, 0x0a, 0x08, 0x42, 0x55, 0x53, 0x5f, 0x48, 0x4f, 0x53, 0x54, 0x10, 0x19, 0x12, 0x0b, 0x0a,
+	0x07, 0x42, 0x55, 0x53, 0x5f, 0x47, 0x53, 0x43, 0x10, 0x1a, 0x12, 0x0d, 0x0a, 0x09, 0x42, 0x55,
+	0x53, 0x5f, 0x41, 0x54, 0x41, 0x52, 0x49, 0x10, 0x1b, 0x12, 0x0b, 0x0a, 0x07, 0x42, 0x55, 0x53,
+	0x5f, 0x53, 0x50, 0x49, 0x10, 0x1c, 0x12, 0x0b, 0x0a, 0x07, 0x42, 0x55, 0x53, 0x5f, 0x52, 0x4d,
+	0x49, 0x10, 0x1d, 0x12, 0x0b, 0x0a, 0x07, 0x42, 0x55, 0x53, 0x5f, 0x43, 0x45, 0x43, 0x10, 0x1e,
+	0x12, 0x13, 0x0a, 0x0f, 0x42, 0x55, 0x53, 0x5f, 0x49, 0x4e, 0x54, 0x45, 0x4c, 0x5f, 0x49, 0x53,
+	0x48, 0x54, 0x50, 0x10, 0x1f, 0x2a, 0xe9, 0x01, 0x0a, 0x04, 0x50, 0x72, 0x6f, 0x70, 0x12, 0x16,
+	0x0a, 0x12, 0x49, 0x4e, 0x50, 0x55, 0x54, 0x5f, 0x50, 0x52, 0x4f, 0x50, 0x5f, 0x50, 0x4f, 0x49,
+	0x4e, 0x54, 0x45, 0x52, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x49, 0x4e, 0x50, 0x55, 0x54, 0x5f,
+	0x50, 0x52, 0x4f, 0x50, 0x5f, 0x44, 0x49, 0x52, 0x45, 0x43, 0x54, 0x10, 0x01, 0x12, 0x18, 0x0a,
+	0x14, 0x49, 0x4e, 0x50, 0x55, 0x54, 0x5f, 0x50, 0x52, 0x4f, 0x50, 0x5f, 0x42, 0x55, 0x54, 0x54,
+	0x4f, 0x4e, 0x50, 0x41, 0x44, 0x10, 0x02, 0x12, 0x16, 0x0a, 0x12, 0x49, 0x4e, 0x50, 0x55, 0x54,
+	0x5f, 0x50, 0x52, 0x4f, 0x50, 0x5f, 0x53, 0x45, 0x4d, 0x49, 0x5f, 0x4d, 0x54, 0x10, 0x03, 0x12,
+	0x1b, 0x0a, 0x17, 0x49, 0x4e, 0x50, 0x55, 0x54, 0x5f, 0x50, 0x52, 0x4f, 0x50, 0x5f, 0x54, 0x4f,
+	0x50, 0x42, 0x55, 0x54, 0x54, 0x4f, 0x4e, 0x50, 0x41, 0x44, 0x10, 0x04, 0x12, 0x1d, 0x0a, 0x19,
+	0x49, 0x4e, 0x50, 0x55, 0x54, 0x5f, 0x50, 0x52, 0x4f, 0x50, 0x5f, 0x50, 0x4f, 0x49, 0x4e, 0x54,
+	0x49, 0x4e, 0x47, 0x5f, 0x53, 0x54, 0x49, 0x43, 0x4b, 0x10, 0x05, 0x12, 0x1c, 0x0a, 0x18, 0x49,
+	0x4e, 0x50, 0x55, 0x54, 0x5f, 0x50, 0x52, 0x4f, 0x50, 0x5f, 0x41, 0x43, 0x43, 0x45, 0x4c, 0x45,
+	0x52, 0x4f, 0x4d, 0x45, 0x54, 0x45, 0x52, 0x10, 0x06, 0x12, 0x12, 0x0a, 0x0e, 0x49, 0x4e, 0x50,
+	0x55, 0x54, 0x5f, 0x50, 0x52, 0x4f, 0x50, 0x5f, 0x4d, 0x41, 0x58, 0x10, 0x1f, 0x12, 0x12, 0x0a,
+	0x0e, 0x49, 0x4e, 0x50, 0x55, 0x54, 0x5f, 0x50, 0x52, 0x4f, 0x50, 0x5f, 0x43, 0x4e, 0x54, 0x10,
+	0x20, 0x2a, 0xde, 0x01, 0x0a, 0x03, 0x52, 0x65, 0x6c, 0x12, 0x09, 0x0a, 0x05, 0x52, 0x45, 0x4c,
+	0x5f, 0x58, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x52, 0x45, 0x4c, 0x5f, 0x59, 0x10, 0x01, 0x12,
+	0x09, 0x0a, 0x05, 0x52, 0x45, 0x4c, 0x5f, 0x5a, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45,
+	0x4c, 0x5f, 0x52, 0x58, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45, 0x4c, 0x5f, 0x52, 0x59,
+	0x10, 0x04, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45, 0x4c, 0x5f, 0x52, 0x5a, 0x10, 0x05, 0x12, 0x0e,
+	0x0a, 0x0a, 0x52, 0x45, 0x4c, 0x5f, 0x48, 0x57, 0x48, 0x45, 0x45, 0x4c, 0x10, 0x06, 0x12, 0x0c,
+	0x0a, 0x08, 0x52, 0x45, 0x4c, 0x5f, 0x44, 0x49, 0x41, 0x4c, 0x10, 0x07, 0x12, 0x0d, 0x0a, 0x09,
+	0x52, 0x45, 0x4c, 0x5f, 0x57, 0x48, 0x45, 0x45, 0x4c, 0x10, 0x08, 0x12, 0x0c, 0x0a, 0x08, 0x52,
+	0x45, 0x4c, 0x5f, 0x4d, 0x49, 0x53, 0x43, 0x10, 0x09, 0x12, 0x10, 0x0a, 0x0c, 0x52, 0x45, 0x4c,
+	0x5f, 0x52, 0x45, 0x53, 0x45, 0x52, 0x56, 0x45, 0x44, 0x10, 0x0a, 0x12, 0x14, 0x0a, 0x10, 0x52,
+	0x45, 0x4c, 0x5f, 0x57, 0x48, 0x45, 0x45, 0x4c, 0x5f, 0x48, 0x49, 0x5f, 0x52, 0x45, 0x53, 0x10,
+	0x0b, 0x12, 0x15, 0x0a, 0x11, 0x52, 0x45, 0x4c, 0x5f, 0x48, 0x57, 0x48, 0x45, 0x45, 0x4c, 0x5f,
+	0x48, 0x49, 0x5f, 0x52, 0x45, 0x53, 0x10, 0x0c, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x45, 0x4c, 0x5f,
+	0x4d, 0x41, 0x58, 0x10, 0x0f, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x45, 0x4c, 0x5f, 0x43, 0x4e, 0x54,
+	0x10, 0x10, 0x2a, 0x8b, 0x06, 0x0a, 0x03, 0x41, 0x62, 0x73, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x42,
+	0x53, 0x5f, 0x58, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x42, 0x53, 0x5f, 0x59, 0x10, 0x01,
+	0x12, 0x09, 0x0a, 0x05, 0x41, 0x42, 0x53, 0x5f, 0x5a, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x41,
+	0x42, 0x53, 0x5f, 0x52, 0x58, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x42, 0x53, 0x5f, 0x52,
+	0x59, 0x10, 0x04, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x42, 0x53, 0x5f, 0x52, 0x5a, 0x10, 0x05, 0x12,
+	0x10, 0x0a, 0x0c, 0x41, 0x42, 0x53, 0x5f, 0x54, 0x48, 0x52, 0x4f, 0x54, 0x54, 0x4c, 0x45, 0x10,
+	0x06, 0x12, 0x0e, 0x0a, 0x0a, 0x41, 0x42, 0x53, 0x5f, 0x52, 0x55, 0x44, 0x44, 0x45, 0x52, 0x10,
+	0x07, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x42, 0x53, 0x5f, 0x57, 0x48, 0x45, 0x45, 0x4c, 0x10, 0x08,
+	0x12, 0x0b, 0x0a, 0x07, 0x41, 0x42, 0x53, 0x5f, 0x47, 0x41, 0x53, 0x10, 0x09, 0x12, 0x0d, 0x0a,
+	0x09, 0x41, 0x42, 0x53, 0x5f, 0x42, 0x52, 0x41, 0x4b, 0x45, 0x10, 0x0a, 0x12, 0x0d, 0x0a, 0x09,
+	0x41, 0x42, 0x53, 0x5f, 0x48, 0x41, 0x54, 0x30, 0x58, 0x10, 0x10, 0x12, 0x0d, 0x0a, 0x09, 0x41,
+	0x42, 0x53, 0x5f, 0x48, 0x41, 0x54, 0x30, 0x59, 0x10, 0x11, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x42,
+	0x53, 0x5f, 0x48, 0x41, 0x54, 0x31, 0x58, 0x10, 0x12, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x42, 0x53,
+	0x5f, 0x48, 0x41, 0x54, 0x31, 0x59, 0x10, 0x13, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x42, 0x53, 0x5f,
+	0x48, 0x41, 0x54, 0x32, 0x58, 0x10, 0x14, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x42, 0x53, 0x5f, 0x48,
+	0x41, 0x54, 0x32, 0x59, 0x10, 0x15, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x42, 0x53, 0x5f, 0x48, 0x41,
+	0x54, 0x33, 0x58, 0x10, 0x16, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x42, 0x53, 0x5f, 0x48, 0x41, 0x54,
+	0x33, 0x59, 0x10, 0x17, 0x12, 0x10, 0x0a, 0x0c, 0x41, 0x42, 0x53, 0x5f, 0x50, 0x52, 0x45, 0x53,
+	0x53, 0x55, 0x52, 0x45, 0x10, 0x18, 0x12, 0x10, 0x0a, 0x0c, 0x41, 0x42, 0x53, 0x5f, 0x44, 0x49,
+	0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x10, 0x19, 0x12, 0x0e, 0x0a, 0x0a, 0x41, 0x42, 0x53, 0x5f,
+	0x54, 0x49, 0x4c, 0x54, 0x5f, 0x58, 0x10, 0x1a, 0x12, 0x0e, 0x0a, 0x0a, 0x41, 0x42, 0x53, 0x5f,
+	0x54, 0x49, 0x4c, 0x54, 0x5f, 0x59, 0x10, 0x1b, 0x12, 0x12, 0x0a, 0x0e, 0x41, 0x42, 0x53, 0x5f,
+	0x54, 0x4f, 0x4f, 0x4c, 0x5f, 0x57, 0x49, 0x44, 0x54, 0x48, 0x10, 0x1c, 0x12, 0x0e, 0x0a, 0x0a,
+	0x41, 0x42, 0x53, 0x5f, 0x56, 0x4f, 0x4c, 0x55, 0x4d, 0x45, 0x10, 0x20, 0x12, 0x0f, 0x0a, 0x0b,
+	0x41, 0x42, 0x53, 0x5f, 0x50, 0x52, 0x4f, 0x46, 0x49, 0x4c, 0x45, 0x10, 0x21, 0x12, 0x0c, 0x0a,
+	0x08, 0x41, 0x42, 0x53, 0x5f, 0x4d, 0x49, 0x53, 0x43, 0x10, 0x28, 0x12, 0x10, 0x0a, 0x0c, 0x41,
+	0x42, 0x53, 0x5f, 0x52, 0x45, 0x53, 0x45, 0x52, 0x56, 0x45, 0x44, 0x10, 0x2e, 0x12, 0x0f, 0x0a,
+	0x0b, 0x41, 0x42, 0x53, 0x5f, 0x4d, 0x54, 0x5f, 0x53, 0x4c, 0x4f, 0x54, 0x10, 0x2f, 0x12, 0x16,
+	0x0a, 0x12, 0x41, 0x42, 0x53, 0x5f, 0x4d, 0x54, 0x5f, 0x54, 0x4f, 0x55, 0x43, 0x48, 0x5f, 0x4d,
+	0x41, 0x4a, 0x4f, 0x52, 0x10, 0x30, 0x12, 0x16, 0x0a, 0x12, 0x41, 0x42, 0x53, 0x5f, 0x4d, 0x54,
+	0x5f, 0x54, 0x4f, 0x55, 0x43, 0x48, 0x5f, 0x4d, 0x49, 0x4e, 0x4f, 0x52, 0x10, 0x31, 0x12, 0x16,
+	0x0a, 0x12, 0x41, 0x42, 0x53, 0x5f, 0x4d, 0x54, 0x5f, 0x57, 0x49, 0x44, 0x54, 0x48, 0x5f, 0x4d,
+	0x41, 0x4a, 0x4f, 0x52, 0x10, 0x32, 0x12, 0x16, 0x0a, 0x12, 0x41, 0x42, 0x53, 0x5f, 0x4d, 0x54,
+	0x5f, 0x57, 0x49, 0x44, 0x54, 0x48, 0x5f, 0x4d, 0x49, 0x4e, 0x4f, 0x52, 0x10, 0x33, 0x12, 0x16,
+	0x0a, 0x12, 0x41, 0x42, 0x53, 0x5f, 0x4d, 0x54, 0x5f, 0x4f, 0x52, 0x49, 0x45, 0x4e, 0x54, 0x41,
+	0x54, 0x49, 0x4f, 0x4e, 0x10, 0x34, 0x12, 0x15, 0x0a, 0x11, 0x41, 0x42, 0x53, 0x5f, 0x4d, 0x54,
+	0x5f, 0x50, 0x4f, 0x53, 0x49, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x58, 0x10, 0x35, 0x12, 0x15, 0x0a,
+	0x11, 0x41, 0x42, 0x53, 0x5f, 0x4d, 0x54, 0x5f, 0x50, 0x4f, 0x53, 0x49, 0x54, 0x49, 0x4f, 0x4e,
+	0x5f, 0x59, 0x10, 0x36, 0x12, 0x14, 0x0a, 0x10, 0x41, 0x42, 0x53, 0x5f, 0x4d, 0x54, 0x5f, 0x54,
+	0x4f, 0x4f, 0x4c, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x10, 0x37, 0x12, 0x12, 0x0a, 0x0e, 0x41, 0x42,
+	0x53, 0x5f, 0x4d, 0x54, 0x5f, 0x42, 0x4c, 0x4f, 0x42, 0x5f, 0x49, 0x44, 0x10, 0x38, 0x12, 0x16,
+	0x0a, 0x12, 0x41, 0x42, 0x53, 0x5f, 0x4d, 0x54, 0x5f, 0x54, 0x52, 0x41, 0x43, 0x4b, 0x49, 0x4e,
+	0x47, 0x5f, 0x49, 0x44, 0x10, 0x39, 0x12, 0x13, 0x0a, 0x0f, 0x41, 0x42, 0x53, 0x5f, 0x4d, 0x54,
+	0x5f, 0x50, 0x52, 0x45, 0x53, 0x53, 0x55, 0x52, 0x45, 0x10, 0x3a, 0x12, 0x13, 0x0a, 0x0f, 0x41,
+	0x42, 0x53, 0x5f, 0x4d, 0x54, 0x5f, 0x44, 0x49, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x10, 0x3b,
+	0x12, 0x11, 0x0a, 0x0d, 0x41, 0x42, 0x53, 0x5f, 0x4d, 0x54, 0x5f, 0x54, 0x4f, 0x4f, 0x4c, 0x5f,
+	0x58, 0x10, 0x3c, 0x12, 0x11, 0x0a, 0x0d, 0x41, 0x42, 0x53, 0x5f, 0x4d, 0x54, 0x5f, 0x54, 0x4f,
+	0x4f, 0x4c, 0x5f, 0x59, 0x10, 0x3d, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x42, 0x53, 0x5f, 0x4d, 0x41,
+	0x58, 0x10, 0x3f, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x42, 0x53, 0x5f, 0x43, 0x4e, 0x54, 0x10, 0x40,
+	0x2a, 0xa1, 0x03, 0x0a, 0x06, 0x53, 0x77, 0x69, 0x74, 0x63, 0x68, 0x12, 0x0a, 0x0a, 0x06, 0x53,
+	0x57, 0x5f, 0x4c, 0x49, 0x44, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x53, 0x57, 0x5f, 0x54, 0x41,
+	0x42, 0x4c, 0x45, 0x54, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x53,
+	0x57, 0x5f, 0x48, 0x45, 0x41, 0x44, 0x50, 0x48, 0x4f, 0x4e, 0x45, 0x5f, 0x49, 0x4e, 0x53, 0x45,
+	0x52, 0x54, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x57, 0x5f, 0x52, 0x46, 0x4b, 0x49, 0x4c,
+	0x4c, 0x5f, 0x41, 0x4c, 0x4c, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x57, 0x5f, 0x52, 0x41,
+	0x44, 0x49, 0x4f, 0x10, 0x03, 0x12, 0x18, 0x0a, 0x14, 0x53, 0x57, 0x5f, 0x4d, 0x49, 0x43, 0x52,
+	0x4f, 0x50, 0x48, 0x4f, 0x4e, 0x45, 0x5f, 0x49, 0x4e, 0x53, 0x45, 0x52, 0x54, 0x10, 0x04, 0x12,
+	0x0b, 0x0a, 0x07, 0x53, 0x57, 0x5f, 0x44, 0x4f, 0x43, 0x4b, 0x10, 0x05, 0x12, 0x15, 0x0a, 0x11,
+	0x53, 0x57, 0x5f, 0x4c, 0x49, 0x4e, 0x45, 0x4f, 0x55, 0x54, 0x5f, 0x49, 0x4e, 0x53, 0x45, 0x52,
+	0x54, 0x10, 0x06, 0x12, 0x1b, 0x0a, 0x17, 0x53, 0x57, 0x5f, 0x4a, 0x41, 0x43, 0x4b, 0x5f, 0x50,
+	0x48, 0x59, 0x53, 0x49, 0x43, 0x41, 0x4c, 0x5f, 0x49, 0x4e, 0x53, 0x45, 0x52, 0x54, 0x10, 0x07,
+	0x12, 0x16, 0x0a, 0x12, 0x53, 0x57, 0x5f, 0x56, 0x49, 0x44, 0x45, 0x4f, 0x4f, 0x55, 0x54, 0x5f,
+	0x49, 0x4e, 0x53, 0x45, 0x52, 0x54, 0x10, 0x08, 0x12, 0x18, 0x0a, 0x14, 0x53, 0x57, 0x5f, 0x43,
+	0x41, 0x4d, 0x45, 0x52, 0x41, 0x5f, 0x4c, 0x45, 0x4e, 0x53, 0x5f, 0x43, 0x4f, 0x56, 0x45, 0x52,
+	0x10, 0x09, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x57, 0x5f, 0x4b, 0x45, 0x59, 0x50, 0x41, 0x44, 0x5f,
+	0x53, 0x4c, 0x49, 0x44, 0x45, 0x10, 0x0a, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x57, 0x5f, 0x46, 0x52,
+	0x4f, 0x4e, 0x54, 0x5f, 0x50, 0x52, 0x4f, 0x58, 0x49, 0x4d, 0x49, 0x54, 0x59, 0x10, 0x0b, 0x12,
+	0x12, 0x0a, 0x0e, 0x53, 0x57, 0x5f, 0x52, 0x4f, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x4c, 0x4f, 0x43,
+	0x4b, 0x10, 0x0c, 0x12, 0x14, 0x0a, 0x10, 0x53, 0x57, 0x5f, 0x4c, 0x49, 0x4e, 0x45, 0x49, 0x4e,
+	0x5f, 0x49, 0x4e, 0x53, 0x45, 0x52, 0x54, 0x10, 0x0d, 0x12, 0x12, 0x0a, 0x0e, 0x53, 0x57, 0x5f,
+	0x4d, 0x55, 0x54, 0x45, 0x5f, 0x44, 0x45, 0x56, 0x49, 0x43, 0x45, 0x10, 0x0e, 0x12, 0x13, 0x0a,
+	0x0f, 0x53, 0x57, 0x5f, 0x50, 0x45, 0x4e, 0x5f, 0x49, 0x4e, 0x53, 0x45, 0x52, 0x54, 0x45, 0x44,
+	0x10, 0x0f, 0x12, 0x14, 0x0a, 0x10, 0x53, 0x57, 0x5f, 0x4d, 0x41, 0x43, 0x48, 0x49, 0x4e, 0x45,
+	0x5f, 0x43, 0x4f, 0x56, 0x45, 0x52, 0x10, 0x10, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x57, 0x5f, 0x4d,
+	0x41, 0x58, 0x10, 0x10, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x57, 0x5f, 0x43, 0x4e, 0x54, 0x10, 0x11,
+	0x1a, 0x02, 0x10, 0x01, 0x2a, 0x4c, 0x0a, 0x05, 0x53, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x0d, 0x0a,
+	0x09, 0x53, 0x4e, 0x44, 0x5f, 0x43, 0x4c, 0x49, 0x43, 0x4b, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08,
+	0x53, 0x4e, 0x44, 0x5f, 0x42, 0x45, 0x4c, 0x4c, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x4e,
+	0x44, 0x5f, 0x54, 0x4f, 0x4e, 0x45, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x4e, 0x44, 0x5f,
+	0x4d, 0x41, 0x58, 0x10, 0x07, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x4e, 0x44, 0x5f, 0x43, 0x4e, 0x54,
+	0x10, 0x08, 0x42, 0x2d, 0x5a, 0x2b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x65, 0x72, 0x64, 0x69, 0x63, 0x68, 0x65, 0x6e, 0x2f, 0x63, 0x68, 0x72, 0x6f, 0x6d, 0x65,
+	0x6b, 0x65, 0x79, 0x2f, 0x65, 0x76, 0x64, 0x65, 0x76, 0x2f, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64,
+	0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2755,10 +3483,16 @@ func file_keycode_proto_rawDescGZIP() []byte {
 	return file_keycode_proto_rawDescData
 }
 
-var file_keycode_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_keycode_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
 var file_keycode_proto_goTypes = []interface{}{
-	(Code)(0), // 0: keycode.Code
-	(LED)(0),  // 1: keycode.LED
+	(Code)(0),   // 0: keycode.Code
+	(LED)(0),    // 1: keycode.LED
+	(Bus)(0),    // 2: keycode.Bus
+	(Prop)(0),   // 3: keycode.Prop
+	(Rel)(0),    // 4: keycode.Rel
+	(Abs)(0),    // 5: keycode.Abs
+	(Switch)(0), // 6: keycode.Switch
+	(Sound)(0),  // 7: keycode.Sound
 }
 var file_keycode_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -2778,7 +3512,7 @@ func file_keycode_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_keycode_proto_rawDesc,
-			NumEnums:      2,
+			NumEnums:      8,
 			NumMessages:   0,
 			NumExtensions: 0,
 			NumServices:   0,
@@ -0,0 +1,248 @@
+package evdev
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/erdichen/chromekey/evdev/keycode"
+	"github.com/erdichen/chromekey/log"
+	"golang.org/x/sys/unix"
+)
+
+// RemapDeviceName is the name of the virtual keyboards created by the remapper. They are never matched as input devices.
+const RemapDeviceName = "Chromebook keyboard remap"
+
+// remapDevices are the identities of the virtual keyboards created by this process, which may have any name.
+var remapDevices struct {
+	sync.Mutex
+	infos []Info
+}
+
+// AddRemapDevice records the identity of a virtual keyboard created by the remapper so that it is never matched as an input device.
+func AddRemapDevice(name, phys string, id InputID) {
+	remapDevices.Lock()
+	defer remapDevices.Unlock()
+	remapDevices.infos = append(remapDevices.infos, Info{Name: name, Phys: phys, ID: id})
+}
+
+// RemoveRemapDevice removes the identity of a closed virtual keyboard recorded by AddRemapDevice.
+func RemoveRemapDevice(name, phys string, id InputID) {
+	remapDevices.Lock()
+	defer remapDevices.Unlock()
+	for i, v := range remapDevices.infos {
+		if v.Name == name && v.Phys == phys && v.ID == id {
+			remapDevices.infos = append(remapDevices.infos[:i], remapDevices.infos[i+1:]...)
+			return
+		}
+	}
+}
+
+// hasRemapName returns true if a virtual keyboard of the remapper has the given name.
+func hasRemapName(name string) bool {
+	remapDevices.Lock()
+	defer remapDevices.Unlock()
+	for _, v := range remapDevices.infos {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isRemapDevice returns true if an input device has the identity of a virtual keyboard of the remapper.
+// A virtual keyboard that copies the identity of its input device is told apart by being virtual.
+func isRemapDevice(in *Device, info *Info) bool {
+	if info.Name == RemapDeviceName {
+		return true
+	}
+	remapDevices.Lock()
+	found := false
+	for _, v := range remapDevices.infos {
+		if v.Name == info.Name && v.Phys == info.Phys && v.ID == info.ID {
+			found = true
+			break
+		}
+	}
+	remapDevices.Unlock()
+	return found && in.IsVirtual()
+}
+
+// MinKeyboardScore is the capability score of a device with most of the keys of a typewriter keyboard.
+const MinKeyboardScore = 50
+
+// Matcher selects input devices. All the set fields must match.
+type Matcher struct {
+	Name      string         // Name sub-string
+	NameRegex *regexp.Regexp // Name regular expression
+	Vendor    uint16
+	Product   uint16
+	Bus       keycode.Bus
+	Phys      string // Physical path sub-string
+	Link      string // Symlink to the device node, e.g. /dev/input/by-id/usb-04d9_USB_Keyboard-event-kbd
+	MinScore  int    // Minimum keyboard capability score
+	Virtual   bool   // Also match virtual devices, e.g. the keyboards of other remappers
+}
+
+// NameMatcher returns a matcher of the devices with a name that contains a sub-string.
+// Named devices may be virtual keyboards other than those of the remapper.
+// An empty name matches the devices with most of the keys of a keyboard that are not virtual.
+func NameMatcher(name string) *Matcher {
+	if name == "" {
+		return &Matcher{MinScore: MinKeyboardScore}
+	}
+	return &Matcher{Name: name, Virtual: true}
+}
+
+// ParseID parses vendor and product IDs in the hexadecimal "vendor:product" format of lsusb.
+func ParseID(s string) (vendor, product uint16, err error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid vendor:product ID: %q", s)
+	}
+	v, err := strconv.ParseUint(parts[0], 16, 16)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid vendor ID: %q", parts[0])
+	}
+	p, err := strconv.ParseUint(parts[1], 16, 16)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid product ID: %q", parts[1])
+	}
+	return uint16(v), uint16(p), nil
+}
+
+// Match returns true if an input device has all the properties of the matcher.
+// The virtual keyboards of the remapper never match.
+func (m *Matcher) Match(in *Device) bool {
+	info, err := m.query(in)
+	if err != nil {
+		log.Errorf("failed to query input device %v: %v", in.Path(), err)
+		return false
+	}
+	return !isRemapDevice(in, info) && m.matchInfo(info) && m.matchDevice(in)
+}
+
+// query returns the identity of an input device with only the fields needed by the matcher and isRemapDevice.
+func (m *Matcher) query(in *Device) (*Info, error) {
+	info := &Info{Path: in.Path()}
+	var err error
+	if info.Name, err = in.GetName(); err != nil {
+		return nil, err
+	}
+	remap := hasRemapName(info.Name)
+	if m.Phys != "" || remap {
+		if info.Phys, err = in.GetPhys(); err != nil && err != unix.ENOENT {
+			return nil, err
+		}
+	}
+	if m.Bus != keycode.Bus_BUS_NONE || m.Vendor != 0 || m.Product != 0 || remap {
+		if info.ID, err = in.GetID(); err != nil {
+			return nil, err
+		}
+	}
+	return info, nil
+}
+
+// matchInfo matches the identity of an input device.
+func (m *Matcher) matchInfo(info *Info) bool {
+	if m.Name != "" && !strings.Contains(info.Name, m.Name) {
+		return false
+	}
+	if m.NameRegex != nil && !m.NameRegex.MatchString(info.Name) {
+		return false
+	}
+	if m.Phys != "" && !strings.Contains(info.Phys, m.Phys) {
+		return false
+	}
+	if m.Bus != keycode.Bus_BUS_NONE && info.ID.BusType != uint16(m.Bus) {
+		return false
+	}
+	if m.Vendor != 0 && info.ID.Vendor != m.Vendor {
+		return false
+	}
+	if m.Product != 0 && info.ID.Product != m.Product {
+		return false
+	}
+	return true
+}
+
+// matchDevice matches the device node and the capabilities of an input device.
+func (m *Matcher) matchDevice(in *Device) bool {
+	if m.Link != "" {
+		link, err := filepath.EvalSymlinks(m.Link)
+		if err != nil {
+			return false
+		}
+		path, err := filepath.EvalSymlinks(in.Path())
+		if err != nil || link != path {
+			return false
+		}
+	}
+	if m.MinScore > 0 && in.KeyboardScore() < m.MinScore {
+		return false
+	}
+	if !m.Virtual && in.IsVirtual() {
+		return false
+	}
+	return true
+}
+
+// KeyboardScore returns the number of typewriter keys of a device, from KEY_ESC to KEY_SPACE.
+// A full keyboard scores 57. Power buttons and media key devices score close to 0.
+func (in *Device) KeyboardScore() int {
+	bits, err := in.GetKeyBits()
+	if err != nil {
+		return 0
+	}
+	score := 0
+	for k := keycode.Code_KEY_ESC; k <= keycode.Code_KEY_SPACE; k++ {
+		if bits.Get(k) {
+			score++
+		}
+	}
+	return score
+}
+
+// IsVirtual returns true if the device is not backed by hardware, e.g. a uinput device.
+func (in *Device) IsVirtual() bool {
+	path, err := filepath.EvalSymlinks(in.Path())
+	if err != nil {
+		return false
+	}
+	sys, err := filepath.EvalSymlinks(filepath.Join("/sys/class/input", filepath.Base(path)))
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(sys, "/sys/devices/virtual/")
+}
+
+// OpenMatching opens all event devices in a directory that match any of the matchers.
+func OpenMatching(devDir string, ms []*Matcher, verbosity int) ([]*Device, error) {
+	all, errList, err := OpenAll(devDir)
+	if err != nil {
+		return nil, err
+	}
+	devs := SelectMatching(all, ms)
+	closeAll(all, devs, verbosity)
+	if len(devs) == 0 {
+		return nil, noDeviceError(errList)
+	}
+	return devs, nil
+}
+
+// SelectMatching returns the devices that match any of the matchers.
+func SelectMatching(devs []*Device, ms []*Matcher) []*Device {
+	var selected []*Device
+	for _, d := range devs {
+		for _, m := range ms {
+			if m.Match(d) {
+				selected = append(selected, d)
+				break
+			}
+		}
+	}
+	return selected
+}
@@ -0,0 +1,59 @@
+package evdev
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// WatchDir watches a directory for new or changed event device nodes and sends their paths to a channel.
+// The channel is closed after ctx is done.
+func WatchDir(ctx context.Context, devDir string) (chan string, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	// Device nodes may not be accessible until udev changes their attributes.
+	if _, err := unix.InotifyAddWatch(fd, devDir, unix.IN_CREATE|unix.IN_ATTRIB); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	f := os.NewFile(uintptr(fd), "inotify")
+
+	go func() {
+		<-ctx.Done()
+		f.Close()
+	}()
+
+	c := make(chan string)
+	go func() {
+		defer close(c)
+		buf := make([]byte, 4096)
+		for {
+			n, err := f.Read(buf)
+			if err != nil {
+				return
+			}
+			for b := buf[:n]; len(b) >= unix.SizeofInotifyEvent; {
+				ev := (*unix.InotifyEvent)(unsafe.Pointer(&b[0]))
+				end := unix.SizeofInotifyEvent + int(ev.Len)
+				name := string(bytes.TrimRight(b[unix.SizeofInotifyEvent:end], "\x00"))
+				b = b[end:]
+				if !strings.HasPrefix(name, "event") {
+					continue
+				}
+				select {
+				case c <- filepath.Join(devDir, name):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return c, nil
+}
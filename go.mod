@@ -6,5 +6,6 @@ require golang.org/x/sys v0.0.0-20211216021012-1d35b9e2eb4e
 
 require (
 	github.com/coreos/go-systemd v0.0.0-20191104093116-d3cd4ed1dbcf
+	github.com/godbus/dbus/v5 v5.1.0
 	google.golang.org/protobuf v1.27.1
 )
@@ -33,3 +33,24 @@ func TestIOC(t *testing.T) {
 		}
 	}
 }
+
+func TestIOR(t *testing.T) {
+	testData := [...][2]uint{
+		{IOR('E', 0x01, 4), uint(testdefs.EVIOCGVERSION)},                                 // int
+		{IOR('E', 0x02, 8), uint(testdefs.EVIOCGID)},                                      // struct input_id
+		{IOC(Read, 'E', 0x07, 256), uint(testdefs.EVIOCGPHYS_256)},                        // char[256]
+		{IOC(Read, 'E', 0x08, 256), uint(testdefs.EVIOCGUNIQ_256)},                        // char[256]
+		{IOC(Read, 'E', 0x09, 4), uint(testdefs.EVIOCGPROP_4)},                            // unsigned long[]
+		{IOC(Read, 'E', 0x1b, 4), uint(testdefs.EVIOCGSW_4)},                              // unsigned long[]
+		{IOR('E', 0x40+0x2f, 24), uint(testdefs.EVIOCGABS_ABS_MT_SLOT)},                   // struct input_absinfo
+		{IOR(testdefs.UINPUT_IOCTL_BASE, 45, 4), uint(testdefs.UI_GET_VERSION)},           // unsigned int
+		{IOC(Read, testdefs.UINPUT_IOCTL_BASE, 44, 64), uint(testdefs.UI_GET_SYSNAME_64)}, // char[64]
+	}
+
+	for i, d := range testData {
+		got, want := d[0], d[1]
+		if got != want {
+			t.Errorf("test %d IOR got %#x want %#x", i, got, want)
+		}
+	}
+}
@@ -2,6 +2,13 @@ package testdefs
 
 /*
 #include <linux/uinput.h>
+
+const unsigned int _EVIOCGPHYS_256 = EVIOCGPHYS(256);
+const unsigned int _EVIOCGUNIQ_256 = EVIOCGUNIQ(256);
+const unsigned int _EVIOCGPROP_4 = EVIOCGPROP(4);
+const unsigned int _EVIOCGSW_4 = EVIOCGSW(4);
+const unsigned int _EVIOCGABS_ABS_MT_SLOT = EVIOCGABS(ABS_MT_SLOT);
+const unsigned int _UI_GET_SYSNAME_64 = UI_GET_SYSNAME(64);
 */
 import "C"
 
@@ -19,4 +26,22 @@ const (
 	UI_SET_PHYS    = C.UI_SET_PHYS
 	UI_SET_SWBIT   = C.UI_SET_SWBIT
 	UI_SET_PROPBIT = C.UI_SET_PROPBIT
+
+	UI_GET_VERSION = C.UI_GET_VERSION
+)
+
+const (
+	EVIOCGVERSION = C.EVIOCGVERSION
+	EVIOCGID      = C.EVIOCGID
+)
+
+var (
+	EVIOCGPHYS_256 = C._EVIOCGPHYS_256
+	EVIOCGUNIQ_256 = C._EVIOCGUNIQ_256
+	EVIOCGPROP_4   = C._EVIOCGPROP_4
+	EVIOCGSW_4     = C._EVIOCGSW_4
+
+	EVIOCGABS_ABS_MT_SLOT = C._EVIOCGABS_ABS_MT_SLOT
+
+	UI_GET_SYSNAME_64 = C._UI_GET_SYSNAME_64
 )
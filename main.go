@@ -8,13 +8,20 @@ import (
 	"io/ioutil"
 	"os"
 	"os/signal"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 
+	"github.com/erdichen/chromekey/control"
 	"github.com/erdichen/chromekey/evdev"
 	"github.com/erdichen/chromekey/evdev/keycode"
 	"github.com/erdichen/chromekey/log"
 	"github.com/erdichen/chromekey/remap"
 	"github.com/erdichen/chromekey/remap/config"
+	"github.com/godbus/dbus/v5"
 	"google.golang.org/protobuf/encoding/prototext"
 )
 
@@ -24,6 +31,9 @@ const description = `Emulates a FN key to convert functions key to media keys. C
   2. Press FN+Shift+key to use third level key mapping.
   3. Run '%s led' to list LED names.
   4. Run '%s key' to list key names.
+  5. Run '%s devices [-json]' to list input devices and whether they are selected.
+  6. Run '%s ctl help' to list the commands of a running remapper.
+  7. Run '%s status -format=plain|waybar|i3bar' to follow the FN lock state of a running remapper in a status bar.
 
 `
 
@@ -54,20 +64,25 @@ var verbosity = flag.Int("v", 0, "Verbose logging level")
 func main() {
 	flag.Usage = func() {
 		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s:\n\n", os.Args[0])
-		fmt.Fprintf(flag.CommandLine.Output(), description, os.Args[0], os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), description, os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0])
 		flag.PrintDefaults()
 		fmt.Fprintf(flag.CommandLine.Output(), "\n")
 	}
-	devicePath := flag.String("input_device", "", "Keyboard input device")
-	keyboardName := flag.String("keyboard_name", "AT Translated", "Open keyboard input device by name sub-string")
+	devicePath := flag.String("input_device", "", "Comma-separated list of keyboard input devices")
+	keyboardName := flag.String("keyboard_name", "AT Translated", "Open keyboard input device by name sub-string, empty opens the keyboard with the most keys")
 	inputDevDir := flag.String("evdev_dir", "/dev/input", "Keyboard input device directory")
 	uinputDev := flag.String("uinput", "/dev/uinput", "User input event injection device")
 	timeout := flag.Duration("timeout", 0, "Exit after seconds since last event (0=disable)")
 	grab := flag.Bool("grab", true, "Grab evdev input device")
+	waitRelease := flag.Bool("wait_release", true, "Wait for all keys to be released before grabbing the input device, otherwise send the pressed keys to the virtual device")
 	cfgFile := flag.String("config_file", "", "Configuration file")
 	dumpConfig := flag.Bool("dump_config", false, "Dump configuration file")
 	useDefault := flag.Bool("use_default", true, "Use default configuration if config_file is not set")
+	hotplug := flag.Bool("hotplug", false, "Watch evdev_dir for new keyboards that match the keyboard names")
 	showKey := flag.Bool("show_key", false, "Show keycodes only and don't remap or forward the keys")
+	controlSocket := flag.String("control_socket", "", "Unix domain socket of the control API, e.g. /run/chromekey.sock, disabled if empty")
+	controlGroup := flag.String("control_group", "", "Group allowed to use the control socket besides root and the user running the remapper")
+	dbusBus := flag.String("dbus", "", "Export the FN lock and layer state on D-Bus: system, session or auto (system bus for root, otherwise session bus), empty disables it")
 	fnKey := keycode.Code_KEY_RESERVED
 	flag.Func("fnkey", "Keycode of the FN key (default KEY_FN13)", func(value string) error {
 		key, ok := keycode.Code_value[value]
@@ -92,6 +107,22 @@ func main() {
 		return
 	}
 
+	if flag.Arg(0) == "status" {
+		if err := runStatus(*controlSocket, flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "ctl" {
+		if err := runCtl(*controlSocket, flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if *showKey {
 		*verbosity += 3
 		*grab = false
@@ -105,30 +136,36 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	var cfg config.RunConfig
-
-	// Loads configuration from the given file if the flag is valid.
-	if *cfgFile != "" {
-		b, err := ioutil.ReadFile(*cfgFile)
-		if err != nil {
-			log.Fatalf("failed to open configuration file: %v", err)
+	// loadConfig loads the configuration from the given file if the flag is valid and applies the flag overrides.
+	loadConfig := func() (config.RunConfig, error) {
+		var cfg config.RunConfig
+		if *cfgFile != "" {
+			b, err := ioutil.ReadFile(*cfgFile)
+			if err != nil {
+				return cfg, fmt.Errorf("failed to open configuration file: %v", err)
+			}
+			var pb config.KeymapConfig
+			if err := prototext.Unmarshal(b, &pb); err != nil {
+				return cfg, fmt.Errorf("failed to marshal configuration proto: %v", err)
+			}
+			cfg = config.FromPBConfig(&pb)
+		} else if *useDefault {
+			cfg = config.DefaultRunConfig()
 		}
-		var pb config.KeymapConfig
-		if err := prototext.Unmarshal(b, &pb); err != nil {
-			log.Fatalf("failed to marshal configuration proto: %v", err)
+
+		if useLED != keycode.LED_CNT {
+			cfg.UseLED = useLED
 		}
-		cfg = config.FromPBConfig(&pb)
-	} else if *useDefault {
-		cfg = config.DefaultRunConfig()
-	}
 
-	if useLED != keycode.LED_CNT {
-		cfg.UseLED = useLED
+		if fnKey != keycode.Code_KEY_RESERVED {
+			// Overrides FN key in config from flag value.
+			cfg.FnKey = fnKey
+		}
+		return cfg, nil
 	}
-
-	if fnKey != keycode.Code_KEY_RESERVED {
-		// Overrides FN key in config from flag value.
-		cfg.FnKey = fnKey
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Fatalf("%v", err)
 	}
 
 	// Dump the configuration and exit. Use this flag to create new default configuration file.
@@ -140,50 +177,199 @@ func main() {
 		return
 	}
 
-	// Opens an evdev device if the devicePath flag is valid.
-	var in *evdev.Device
-	if *devicePath != "" {
-		d, err := evdev.OpenDevice(*devicePath)
+	// Keyboards are selected by the names and matchers in the configuration, or else by the keyboardName flag.
+	var ms []*evdev.Matcher
+	for _, n := range cfg.KeyboardNames {
+		ms = append(ms, evdev.NameMatcher(n))
+	}
+	for _, k := range cfg.Keyboards {
+		m, err := k.Matcher()
 		if err != nil {
-			log.Fatalf("failed to create open evdev device: %v", err)
+			log.Fatalf("invalid keyboard matcher: %v", err)
 		}
-		if d.IsKeyboard() {
-			in = d
+		ms = append(ms, m)
+	}
+	byName := len(ms) == 0
+	if byName {
+		ms = append(ms, evdev.NameMatcher(*keyboardName))
+	}
+
+	if flag.Arg(0) == "devices" {
+		// Selects devices with the same rules as the remapper below.
+		selectDevs := func(devs []*evdev.Device) []*evdev.Device {
+			if *devicePath != "" {
+				var ins []*evdev.Device
+				for _, p := range strings.Split(*devicePath, ",") {
+					for _, d := range devs {
+						if d.Path() == filepath.Clean(p) && d.IsKeyboard() {
+							ins = append(ins, d)
+						}
+					}
+				}
+				if len(ins) > 0 {
+					return ins
+				}
+			}
+			if byName {
+				if d := evdev.SelectByName(devs, *keyboardName); d != nil {
+					return []*evdev.Device{d}
+				}
+				return nil
+			}
+			return evdev.SelectMatching(devs, ms)
+		}
+		if err := listDevices(*inputDevDir, selectDevs, flag.Args()[1:]); err != nil {
+			log.Fatalf("failed to list input devices: %v", err)
 		}
+		return
 	}
-	// If devicePath does not specify a valid device, try to open an input device in the inputDevDir directory.
-	if in == nil {
-		d, err := evdev.OpenByName(*inputDevDir, *keyboardName, *verbosity)
-		if err != nil {
-			log.Fatalf("failed to create open evdev device: %v", err)
+
+	// Opens the evdev devices if the devicePath flag is valid.
+	var ins []*evdev.Device
+	if *devicePath != "" {
+		for _, p := range strings.Split(*devicePath, ",") {
+			d, err := evdev.OpenDevice(p)
+			if err != nil {
+				log.Fatalf("failed to create open evdev device: %v", err)
+			}
+			if d.IsKeyboard() {
+				ins = append(ins, d)
+			} else {
+				d.Close()
+			}
+		}
+	}
+	// If devicePath does not specify a valid device, try to open input devices in the inputDevDir directory.
+	if len(ins) == 0 {
+		if !byName {
+			ds, err := evdev.OpenMatching(*inputDevDir, ms, *verbosity)
+			if err != nil && !*hotplug {
+				log.Fatalf("failed to create open evdev device: %v", err)
+			}
+			ins = ds
+		} else {
+			d, err := evdev.OpenByName(*inputDevDir, *keyboardName, *verbosity)
+			if err == nil {
+				ins = append(ins, d)
+			} else if !*hotplug {
+				log.Fatalf("failed to create open evdev device: %v", err)
+			}
 		}
-		in = d
 	}
 
 	if *showKey {
-		readAndPrintKeys(ctx, in, sigC)
+		readAndPrintKeys(ctx, ins, sigC)
 		return
 	}
 
 	// Create new remapper instance.
-	s, err := remap.New(ctx, in, *uinputDev, cfg, *grab)
+	opts := remap.Options{
+		OutputDev:   *uinputDev,
+		Grab:        *grab,
+		WaitRelease: *waitRelease,
+	}
+	if *hotplug {
+		opts.DevDir = *inputDevDir
+		opts.Match = func(d *evdev.Device) bool {
+			if !d.IsKeyboard() {
+				return false
+			}
+			for _, m := range ms {
+				if m.Match(d) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	s, err := remap.New(ctx, ins, cfg, opts)
 	if err != nil {
 		log.Fatalf("failed to create key remapper: %v", err)
 	}
 	defer s.Close()
 
-	evC := remap.StartReadEventsLoop(ctx, in)
+	if *controlSocket != "" {
+		srv := &control.Server{State: s}
+		if *cfgFile != "" {
+			srv.Reload = loadConfig
+		}
+		if *controlGroup != "" {
+			g, err := user.LookupGroup(*controlGroup)
+			if err != nil {
+				log.Fatalf("invalid control group: %v", err)
+			}
+			gid, err := strconv.ParseUint(g.Gid, 10, 32)
+			if err != nil {
+				log.Fatalf("invalid control group: %v", err)
+			}
+			srv.AllowGIDs = append(srv.AllowGIDs, uint32(gid))
+		}
+		if err := srv.Listen(ctx, *controlSocket); err != nil {
+			log.Errorf("failed to create control socket: %v", err)
+		}
+	}
+	if *dbusBus != "" {
+		// ExportDBus needs the event loop, so it runs after Start.
+		go func() {
+			if err := exportDBus(ctx, *dbusBus, s); err != nil {
+				log.Errorf("failed to export D-Bus object: %v", err)
+			}
+		}()
+	}
 
 	// Start the remapper event loop.
-	if err := s.Start(ctx, sigC, evC, *timeout); err != nil {
+	if err := s.Start(ctx, sigC, *timeout); err != nil {
 		log.Fatalf("key remapper stopped: %v", err)
 	}
 }
 
+// exportDBus connects to the given bus and exports the state of the remapper on it.
+func exportDBus(ctx context.Context, bus string, s *remap.State) error {
+	if bus == "auto" {
+		bus = "session"
+		if os.Getuid() == 0 {
+			bus = "system"
+		}
+	}
+	var conn *dbus.Conn
+	var err error
+	switch bus {
+	case "system":
+		conn, err = dbus.ConnectSystemBus(dbus.WithContext(ctx))
+	case "session":
+		conn, err = dbus.ConnectSessionBus(dbus.WithContext(ctx))
+	default:
+		return fmt.Errorf("invalid bus: %q", bus)
+	}
+	if err != nil {
+		return err
+	}
+	if err := control.ExportDBus(ctx, conn, s); err != nil {
+		conn.Close()
+		return err
+	}
+	return nil
+}
+
 // readAndPrintKeys prints keycodes to help with writing the configuration file.
-func readAndPrintKeys(ctx context.Context, in *evdev.Device, sigC chan os.Signal) {
-	defer in.Close()
-	evC := remap.StartReadEventsLoop(ctx, in)
+func readAndPrintKeys(ctx context.Context, ins []*evdev.Device, sigC chan os.Signal) {
+	// Merge the events of all input devices until they have all stopped.
+	evC := make(chan []evdev.InputEvent)
+	var wg sync.WaitGroup
+	for _, in := range ins {
+		defer in.Close()
+		wg.Add(1)
+		go func(c chan []evdev.InputEvent) {
+			defer wg.Done()
+			for events := range c {
+				evC <- events
+			}
+		}(remap.StartReadEventsLoop(ctx, in))
+	}
+	go func() {
+		wg.Wait()
+		close(evC)
+	}()
 	done := false
 	for !done {
 		select {
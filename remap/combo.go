@@ -0,0 +1,178 @@
+package remap
+
+import (
+	"time"
+
+	"github.com/erdichen/chromekey/evdev"
+	"github.com/erdichen/chromekey/evdev/keycode"
+	"github.com/erdichen/chromekey/log"
+	"github.com/erdichen/chromekey/remap/config"
+)
+
+// combo is the runtime state of a fired combo.
+type combo struct {
+	cfg      config.ComboConfig
+	held     keycode.KeyBits // Combo keys still held down.
+	released bool            // The combo output key has been released.
+}
+
+// comboState detects combos. Key events are buffered while the pressed keys may still complete a combo.
+type comboState struct {
+	cfgs    []config.ComboConfig
+	buffer  []evdev.InputEvent
+	pressed []keycode.Code // Buffered key presses in order.
+	start   time.Time      // Time of the first buffered key press.
+	active  []*combo
+}
+
+func newComboState(cfgs []config.ComboConfig) comboState {
+	return comboState{cfgs: cfgs}
+}
+
+// candidates returns the combos that contain all the keys.
+func (cs *comboState) candidates(keys []keycode.Code) []config.ComboConfig {
+	var cands []config.ComboConfig
+	for _, c := range cs.cfgs {
+		if len(keys) > len(c.Keys) {
+			continue
+		}
+		match := true
+		for _, k := range keys {
+			match = match && c.HasKey(k)
+		}
+		if match {
+			cands = append(cands, c)
+		}
+	}
+	return cands
+}
+
+// deadline returns when the buffered key presses stop waiting for a combo.
+func (cs *comboState) deadline() (time.Time, bool) {
+	if len(cs.pressed) == 0 {
+		return time.Time{}, false
+	}
+	var timeout time.Duration
+	for _, c := range cs.candidates(cs.pressed) {
+		if t := c.ComboTimeout(); t > timeout {
+			timeout = t
+		}
+	}
+	return cs.start.Add(timeout), true
+}
+
+// processCombo detects combos before a key event goes through tap-hold resolution.
+func (src *source) processCombo(ev evdev.InputEvent, now time.Time) []evdev.InputEvent {
+	cs := &src.combo
+	key := keycode.Code(ev.Code)
+
+	for i, c := range cs.active {
+		if !c.held.Get(key) {
+			continue
+		}
+		switch ev.Value {
+		case 0:
+			var events []evdev.InputEvent
+			c.held.Set(key, false)
+			if !c.released {
+				c.released = true
+				events = GenKey(c.cfg.To, 0)
+			}
+			if c.held.IsZero() {
+				cs.active = append(cs.active[:i], cs.active[i+1:]...)
+			}
+			return events
+		case 2:
+			if !c.released {
+				return GenKey(c.cfg.To, 2)
+			}
+		}
+		return nil
+	}
+
+	if len(cs.pressed) > 0 {
+		if ev.Value == 2 {
+			// Drop auto-repeats while waiting for a combo.
+			return nil
+		}
+		if ev.Value == 1 && len(cs.candidates(append(cs.pressed, key))) > 0 {
+			cs.buffer = append(cs.buffer, ev)
+			cs.pressed = append(cs.pressed, key)
+			return src.checkCombo(now, false)
+		}
+		// Any other key event breaks the combo.
+		cs.buffer = append(cs.buffer, ev)
+		return src.flushCombo(now)
+	}
+
+	if ev.Value == 1 && len(cs.candidates([]keycode.Code{key})) > 0 {
+		cs.buffer = []evdev.InputEvent{ev}
+		cs.pressed = []keycode.Code{key}
+		cs.start = now
+		return src.checkCombo(now, false)
+	}
+	return src.processTapHold(ev, now)
+}
+
+// checkCombo fires a combo if all its keys are pressed and no longer combo is possible or the combo has timed out.
+// It replays the buffered key events if no combo is possible.
+func (src *source) checkCombo(now time.Time, timeout bool) []evdev.InputEvent {
+	cs := &src.combo
+	var fire *config.ComboConfig
+	longer := false
+	cands := cs.candidates(cs.pressed)
+	for i, c := range cands {
+		if len(c.Keys) == len(cs.pressed) {
+			fire = &cands[i]
+		} else {
+			longer = true
+		}
+	}
+	if fire == nil || (longer && !timeout) {
+		if timeout {
+			return src.flushCombo(now)
+		}
+		return nil
+	}
+
+	if verbosity > 0 {
+		log.Infof("combo %v to %v", cs.pressed, fire.To)
+	}
+	c := &combo{cfg: *fire}
+	for _, k := range cs.pressed {
+		c.held.Set(k, true)
+	}
+	cs.active = append(cs.active, c)
+	cs.buffer = nil
+	cs.pressed = nil
+	return GenKey(c.cfg.To, 1)
+}
+
+// releaseCombos replays the buffered key events and releases the outputs of the fired combos.
+func (src *source) releaseCombos(now time.Time) []evdev.InputEvent {
+	var events []evdev.InputEvent
+	for len(src.combo.pressed) > 0 {
+		events = append(events, src.flushCombo(now)...)
+	}
+	for _, c := range src.combo.active {
+		if !c.released {
+			events = append(events, GenKey(c.cfg.To, 0)...)
+		}
+	}
+	src.combo.active = nil
+	return events
+}
+
+// flushCombo passes the first buffered key event on and runs the rest through combo detection again.
+func (src *source) flushCombo(now time.Time) []evdev.InputEvent {
+	cs := &src.combo
+	buffer := cs.buffer
+	cs.buffer = nil
+	cs.pressed = nil
+
+	events := src.processTapHold(buffer[0], now)
+	for _, ev := range buffer[1:] {
+		events = append(events, src.processCombo(ev, now)...)
+	}
+	return events
+}
@@ -1,63 +1,569 @@
 package config
 
 import (
+	"fmt"
+	"regexp"
 	"sort"
+	"time"
 
+	"github.com/erdichen/chromekey/evdev"
 	keycode "github.com/erdichen/chromekey/evdev/keycode"
 )
 
-// FromPBKeymap converts a slice of key map entry protos to a Go map.
-func FromPBKeymap(from []*KeymapEntry) map[keycode.Code]keycode.Code {
-	to := make(map[keycode.Code]keycode.Code)
+// MacroStepConfig is a step of a macro.
+type MacroStepConfig struct {
+	Action MacroAction   `json:"action"`
+	Key    keycode.Code  `json:"key"`
+	Delay  time.Duration `json:"delay"`
+}
+
+// KeyAction is the output of a mapped key. It either sends a key with modifiers or plays a macro.
+// An action only matches while its required modifiers are held and its forbidden modifiers are not.
+type KeyAction struct {
+	To           keycode.Code      `json:"to"`
+	Macro        []MacroStepConfig `json:"macro,omitempty"`
+	AddMods      []keycode.Code    `json:"add_mod,omitempty"`
+	SuppressMods []keycode.Code    `json:"suppress_mod,omitempty"`
+	RequireMods  []keycode.Code    `json:"require_mod,omitempty"`
+	ForbidMods   []keycode.Code    `json:"forbid_mod,omitempty"`
+}
+
+// Clone returns a deep copy of a KeyAction.
+func (a KeyAction) Clone() KeyAction {
+	ka := a
+	ka.Macro = append([]MacroStepConfig(nil), a.Macro...)
+	ka.AddMods = append([]keycode.Code(nil), a.AddMods...)
+	ka.SuppressMods = append([]keycode.Code(nil), a.SuppressMods...)
+	ka.RequireMods = append([]keycode.Code(nil), a.RequireMods...)
+	ka.ForbidMods = append([]keycode.Code(nil), a.ForbidMods...)
+	return ka
+}
+
+// Keymap maps keycodes to key actions. The first matching action of a key is used.
+type Keymap map[keycode.Code][]KeyAction
+
+// KeymapOf returns a Keymap that maps keycodes to keycodes.
+func KeymapOf(m map[keycode.Code]keycode.Code) Keymap {
+	km := make(Keymap)
+	for k, v := range m {
+		km[k] = []KeyAction{{To: v}}
+	}
+	return km
+}
+
+// Set replaces the actions of a key with a single action.
+func (km Keymap) Set(key keycode.Code, a KeyAction) {
+	km[key] = []KeyAction{a}
+}
+
+// Clone returns a deep copy of a Keymap.
+func (km Keymap) Clone() Keymap {
+	to := make(Keymap)
+	for k, v := range km {
+		for _, a := range v {
+			to[k] = append(to[k], a.Clone())
+		}
+	}
+	return to
+}
+
+// FromPBKeymap converts a slice of key map entry protos to a Keymap. Entries of the same key keep their order.
+func FromPBKeymap(from []*KeymapEntry) Keymap {
+	to := make(Keymap)
 	for _, v := range from {
-		to[keycode.Code(v.From)] = keycode.Code(v.To)
+		a := KeyAction{To: keycode.Code(v.To)}
+		a.AddMods = append(a.AddMods, v.AddMod...)
+		a.SuppressMods = append(a.SuppressMods, v.SuppressMod...)
+		a.RequireMods = append(a.RequireMods, v.RequireMod...)
+		a.ForbidMods = append(a.ForbidMods, v.ForbidMod...)
+		for _, m := range v.Macro {
+			a.Macro = append(a.Macro, MacroStepConfig{
+				Action: m.Action,
+				Key:    m.Key,
+				Delay:  time.Duration(m.DelayMs) * time.Millisecond,
+			})
+		}
+		to[keycode.Code(v.From)] = append(to[keycode.Code(v.From)], a)
 	}
 	return to
 }
 
-// ToPBKeymap converts a Go map to a slice of key map entry protos.
-func ToPBKeymap(from map[keycode.Code]keycode.Code) (to []*KeymapEntry) {
-	for k, v := range from {
-		e := &KeymapEntry{
-			From: keycode.Code(k),
-			To:   keycode.Code(v),
+// ToPBKeymap converts a Keymap to a slice of key map entry protos.
+func ToPBKeymap(from Keymap) (to []*KeymapEntry) {
+	for k, actions := range from {
+		for _, v := range actions {
+			e := &KeymapEntry{
+				From:        keycode.Code(k),
+				To:          keycode.Code(v.To),
+				AddMod:      append([]keycode.Code(nil), v.AddMods...),
+				SuppressMod: append([]keycode.Code(nil), v.SuppressMods...),
+				RequireMod:  append([]keycode.Code(nil), v.RequireMods...),
+				ForbidMod:   append([]keycode.Code(nil), v.ForbidMods...),
+			}
+			for _, m := range v.Macro {
+				e.Macro = append(e.Macro, &MacroStep{
+					Action:  m.Action,
+					Key:     m.Key,
+					DelayMs: uint32(m.Delay / time.Millisecond),
+				})
+			}
+			to = append(to, e)
 		}
-		to = append(to, e)
 	}
 	sort.SliceStable(to, func(i, j int) bool { return to[i].From < to[j].From })
 	return
 }
 
+// LayerConfig is a named key map that is activated by its own keys.
+type LayerConfig struct {
+	Name         string         `json:"name"`
+	Mode         LayerMode      `json:"mode"`
+	Enabled      bool           `json:"enabled"`
+	Transparent  bool           `json:"transparent"`
+	InvertLayer  string         `json:"invert_layer"`
+	ReleaseKeys  bool           `json:"release_keys"`
+	SendKey      keycode.Code   `json:"send_key"`
+	Keys         []keycode.Code `json:"key"`
+	RequireLayer []string       `json:"require_layer"`
+	KeyMap       Keymap         `json:"key_map"`
+}
+
+// Clone returns a deep copy of a LayerConfig.
+func (l LayerConfig) Clone() LayerConfig {
+	lc := l
+	lc.Keys = append([]keycode.Code{}, l.Keys...)
+	lc.RequireLayer = append([]string{}, l.RequireLayer...)
+	lc.KeyMap = l.KeyMap.Clone()
+	return lc
+}
+
+// HasKey returns true if key is one of the layer's activation keys.
+func (l LayerConfig) HasKey(key keycode.Code) bool {
+	for _, k := range l.Keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// FromPBLayer creates a LayerConfig from a Layer proto.
+func FromPBLayer(pb *Layer) LayerConfig {
+	return LayerConfig{
+		Name:         pb.Name,
+		Mode:         pb.Mode,
+		Enabled:      pb.Enabled,
+		Transparent:  pb.Transparent,
+		InvertLayer:  pb.InvertLayer,
+		ReleaseKeys:  pb.ReleaseKeys,
+		SendKey:      pb.SendKey,
+		Keys:         append([]keycode.Code{}, pb.Key...),
+		RequireLayer: append([]string{}, pb.RequireLayer...),
+		KeyMap:       FromPBKeymap(pb.KeyMap),
+	}
+}
+
+// ToPBLayer creates a Layer proto from a LayerConfig.
+func ToPBLayer(l LayerConfig) *Layer {
+	return &Layer{
+		Name:         l.Name,
+		Mode:         l.Mode,
+		Enabled:      l.Enabled,
+		Transparent:  l.Transparent,
+		InvertLayer:  l.InvertLayer,
+		ReleaseKeys:  l.ReleaseKeys,
+		SendKey:      l.SendKey,
+		Key:          append([]keycode.Code{}, l.Keys...),
+		RequireLayer: append([]string{}, l.RequireLayer...),
+		KeyMap:       ToPBKeymap(l.KeyMap),
+	}
+}
+
+// DefaultTapHoldTimeout is the hold timeout of a tap-hold key without a configured timeout.
+const DefaultTapHoldTimeout = 200 * time.Millisecond
+
+// TapHoldConfig is a dual-role key that acts differently when tapped or held.
+type TapHoldConfig struct {
+	Key            keycode.Code  `json:"key"`
+	Tap            keycode.Code  `json:"tap"`
+	Hold           keycode.Code  `json:"hold"`
+	HoldLayer      string        `json:"hold_layer"`
+	Timeout        time.Duration `json:"timeout"`
+	PermissiveHold bool          `json:"permissive_hold"`
+	QuickTap       time.Duration `json:"quick_tap"`
+}
+
+// TapKey returns the key sent when the key is tapped.
+func (th TapHoldConfig) TapKey() keycode.Code {
+	if th.Tap == keycode.Code_KEY_RESERVED {
+		return th.Key
+	}
+	return th.Tap
+}
+
+// HoldTimeout returns the time a key must be held to act as held.
+func (th TapHoldConfig) HoldTimeout() time.Duration {
+	if th.Timeout == 0 {
+		return DefaultTapHoldTimeout
+	}
+	return th.Timeout
+}
+
+// FromPBTapHold creates a TapHoldConfig from a TapHold proto.
+func FromPBTapHold(pb *TapHold) TapHoldConfig {
+	return TapHoldConfig{
+		Key:            pb.Key,
+		Tap:            pb.Tap,
+		Hold:           pb.Hold,
+		HoldLayer:      pb.HoldLayer,
+		Timeout:        time.Duration(pb.TimeoutMs) * time.Millisecond,
+		PermissiveHold: pb.PermissiveHold,
+		QuickTap:       time.Duration(pb.QuickTapMs) * time.Millisecond,
+	}
+}
+
+// ToPBTapHold creates a TapHold proto from a TapHoldConfig.
+func ToPBTapHold(th TapHoldConfig) *TapHold {
+	return &TapHold{
+		Key:            th.Key,
+		Tap:            th.Tap,
+		Hold:           th.Hold,
+		HoldLayer:      th.HoldLayer,
+		TimeoutMs:      uint32(th.Timeout / time.Millisecond),
+		PermissiveHold: th.PermissiveHold,
+		QuickTapMs:     uint32(th.QuickTap / time.Millisecond),
+	}
+}
+
+// DefaultComboTimeout is the time to press all keys of a combo without a configured timeout.
+const DefaultComboTimeout = 50 * time.Millisecond
+
+// ComboConfig is a chord of keys pressed together that sends another key.
+type ComboConfig struct {
+	Keys    []keycode.Code `json:"key"`
+	To      keycode.Code   `json:"to"`
+	Timeout time.Duration  `json:"timeout"`
+}
+
+// ComboTimeout returns the time to press all keys of the combo.
+func (c ComboConfig) ComboTimeout() time.Duration {
+	if c.Timeout == 0 {
+		return DefaultComboTimeout
+	}
+	return c.Timeout
+}
+
+// HasKey returns true if key is one of the combo keys.
+func (c ComboConfig) HasKey(key keycode.Code) bool {
+	for _, k := range c.Keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// FromPBCombo creates a ComboConfig from a Combo proto.
+func FromPBCombo(pb *Combo) ComboConfig {
+	return ComboConfig{
+		Keys:    append([]keycode.Code{}, pb.Key...),
+		To:      pb.To,
+		Timeout: time.Duration(pb.TimeoutMs) * time.Millisecond,
+	}
+}
+
+// ToPBCombo creates a Combo proto from a ComboConfig.
+func ToPBCombo(c ComboConfig) *Combo {
+	return &Combo{
+		Key:       append([]keycode.Code{}, c.Keys...),
+		To:        c.To,
+		TimeoutMs: uint32(c.Timeout / time.Millisecond),
+	}
+}
+
+// DefaultBlinkPeriod is the blink period of an LED binding without a configured period.
+const DefaultBlinkPeriod = time.Second
+
+// LEDBindingConfig lights an LED while a remapper state is active.
+type LEDBindingConfig struct {
+	LED     keycode.LED   `json:"led"`
+	State   LEDState      `json:"state"`
+	Layer   string        `json:"layer"`
+	Pattern LEDPattern    `json:"pattern"`
+	Period  time.Duration `json:"period"`
+}
+
+// BlinkPeriod returns the blink period of the binding.
+func (b LEDBindingConfig) BlinkPeriod() time.Duration {
+	if b.Period <= 0 {
+		return DefaultBlinkPeriod
+	}
+	return b.Period
+}
+
+// EffectiveLEDs returns the LED bindings including the FN lock LED selected by UseLED.
+func (cfg RunConfig) EffectiveLEDs() []LEDBindingConfig {
+	var leds []LEDBindingConfig
+	if cfg.UseLED < keycode.LED_CNT {
+		leds = append(leds, LEDBindingConfig{LED: cfg.UseLED, State: LEDState_FN_LOCK})
+	}
+	return append(leds, cfg.LEDs...)
+}
+
+// FromPBLEDBinding creates a LEDBindingConfig from a LEDBinding proto.
+func FromPBLEDBinding(pb *LEDBinding) LEDBindingConfig {
+	return LEDBindingConfig{
+		LED:     pb.Led,
+		State:   pb.State,
+		Layer:   pb.Layer,
+		Pattern: pb.Pattern,
+		Period:  time.Duration(pb.PeriodMs) * time.Millisecond,
+	}
+}
+
+// ToPBLEDBinding creates a LEDBinding proto from a LEDBindingConfig.
+func ToPBLEDBinding(b LEDBindingConfig) *LEDBinding {
+	return &LEDBinding{
+		Led:      b.LED,
+		State:    b.State,
+		Layer:    b.Layer,
+		Pattern:  b.Pattern,
+		PeriodMs: uint32(b.Period / time.Millisecond),
+	}
+}
+
+// DeviceMatchConfig selects input devices by their properties. Zero fields match any device.
+type DeviceMatchConfig struct {
+	Name      string      `json:"name"`
+	NameRegex string      `json:"name_regex"`
+	ID        string      `json:"id"`
+	Bus       keycode.Bus `json:"bus"`
+	Phys      string      `json:"phys"`
+	Link      string      `json:"link"`
+	MinScore  int         `json:"min_score"`
+	Virtual   bool        `json:"virtual"`
+}
+
+// Matcher returns an input device matcher, or an error if the regular expression or the IDs are invalid.
+func (m DeviceMatchConfig) Matcher() (*evdev.Matcher, error) {
+	em := &evdev.Matcher{
+		Name:     m.Name,
+		Bus:      m.Bus,
+		Phys:     m.Phys,
+		Link:     m.Link,
+		MinScore: m.MinScore,
+		Virtual:  m.Virtual,
+	}
+	if m.NameRegex != "" {
+		re, err := regexp.Compile(m.NameRegex)
+		if err != nil {
+			return nil, err
+		}
+		em.NameRegex = re
+	}
+	if m.ID != "" {
+		v, p, err := evdev.ParseID(m.ID)
+		if err != nil {
+			return nil, err
+		}
+		em.Vendor, em.Product = v, p
+	}
+	return em, nil
+}
+
+// FromPBDeviceMatch creates a DeviceMatchConfig from a DeviceMatch proto.
+func FromPBDeviceMatch(pb *DeviceMatch) DeviceMatchConfig {
+	return DeviceMatchConfig{
+		Name:      pb.GetName(),
+		NameRegex: pb.GetNameRegex(),
+		ID:        pb.GetId(),
+		Bus:       pb.GetBus(),
+		Phys:      pb.GetPhys(),
+		Link:      pb.GetLink(),
+		MinScore:  int(pb.GetMinScore()),
+		Virtual:   pb.GetVirtual(),
+	}
+}
+
+// ToPBDeviceMatch creates a DeviceMatch proto from a DeviceMatchConfig.
+func ToPBDeviceMatch(m DeviceMatchConfig) *DeviceMatch {
+	return &DeviceMatch{
+		Name:      m.Name,
+		NameRegex: m.NameRegex,
+		Id:        m.ID,
+		Bus:       m.Bus,
+		Phys:      m.Phys,
+		Link:      m.Link,
+		MinScore:  int32(m.MinScore),
+		Virtual:   m.Virtual,
+	}
+}
+
+// VirtualDeviceConfig is the identity of the virtual keyboards. Zero fields keep the default or copied values.
+type VirtualDeviceConfig struct {
+	Name       string      `json:"name"`
+	ID         string      `json:"id"`
+	Bus        keycode.Bus `json:"bus"`
+	Version    uint32      `json:"version"`
+	Phys       string      `json:"phys"`
+	CopySource bool        `json:"copy_source"`
+	Suffix     string      `json:"suffix"`
+}
+
+// Identity returns the name, phys and IDs of a virtual keyboard given the identity of its input device.
+func (v VirtualDeviceConfig) Identity(src *evdev.Info) (name, phys string, id evdev.InputID, err error) {
+	name = evdev.RemapDeviceName
+	id = evdev.InputID{BusType: uint16(keycode.Bus_BUS_USB), Vendor: 1, Product: 1, Version: 9999}
+	if v.CopySource && src != nil {
+		name, phys, id = src.Name+v.Suffix, src.Phys, src.ID
+	}
+	if v.Name != "" {
+		name = v.Name
+	}
+	if v.ID != "" {
+		if id.Vendor, id.Product, err = evdev.ParseID(v.ID); err != nil {
+			return "", "", evdev.InputID{}, err
+		}
+	}
+	if v.Bus != keycode.Bus_BUS_NONE {
+		id.BusType = uint16(v.Bus)
+	}
+	if v.Version > 0xffff {
+		return "", "", evdev.InputID{}, fmt.Errorf("invalid virtual device version: %#x", v.Version)
+	}
+	if v.Version != 0 {
+		id.Version = uint16(v.Version)
+	}
+	if v.Phys != "" {
+		phys = v.Phys
+	}
+	return name, phys, id, nil
+}
+
+// FromPBVirtualDevice creates a VirtualDeviceConfig from a VirtualDevice proto.
+func FromPBVirtualDevice(pb *VirtualDevice) VirtualDeviceConfig {
+	return VirtualDeviceConfig{
+		Name:       pb.GetName(),
+		ID:         pb.GetId(),
+		Bus:        pb.GetBus(),
+		Version:    pb.GetVersion(),
+		Phys:       pb.GetPhys(),
+		CopySource: pb.GetCopySource(),
+		Suffix:     pb.GetSuffix(),
+	}
+}
+
+// ToPBVirtualDevice creates a VirtualDevice proto from a VirtualDeviceConfig.
+func ToPBVirtualDevice(v VirtualDeviceConfig) *VirtualDevice {
+	if v == (VirtualDeviceConfig{}) {
+		return nil
+	}
+	return &VirtualDevice{
+		Name:       v.Name,
+		Id:         v.ID,
+		Bus:        v.Bus,
+		Version:    v.Version,
+		Phys:       v.Phys,
+		CopySource: v.CopySource,
+		Suffix:     v.Suffix,
+	}
+}
+
+// DeviceConfig is the configuration of the input devices selected by a device section.
+type DeviceConfig struct {
+	Match    DeviceMatchConfig `json:"match"`
+	Fallback bool              `json:"fallback"`
+	Config   RunConfig         `json:"config"`
+}
+
+// FromPBDevice creates a DeviceConfig from a Device proto. The shorthand fields fill in the unset fields of the match.
+func FromPBDevice(pb *Device) DeviceConfig {
+	d := DeviceConfig{
+		Match:    FromPBDeviceMatch(pb.GetMatch()),
+		Fallback: pb.Fallback,
+	}
+	if d.Match.Name == "" {
+		d.Match.Name = pb.Name
+	}
+	if d.Match.ID == "" && (pb.Vendor != 0 || pb.Product != 0) {
+		d.Match.ID = fmt.Sprintf("%04x:%04x", pb.Vendor, pb.Product)
+	}
+	if d.Match.Bus == keycode.Bus_BUS_NONE {
+		d.Match.Bus = pb.Bus
+	}
+	if d.Match.Phys == "" {
+		d.Match.Phys = pb.Phys
+	}
+	if pb.Config != nil {
+		d.Config = FromPBConfig(pb.Config)
+	} else {
+		// A section without a config uses the default key maps and binds no FN lock LED.
+		d.Config = DefaultRunConfig()
+		d.Config.UseLED = keycode.LED_CNT
+	}
+	d.Config.OutputMode = OutputMode_SHARED_OUTPUT
+	d.Config.VirtualDevice = VirtualDeviceConfig{}
+	d.Config.KeyboardNames = nil
+	d.Config.Keyboards = nil
+	d.Config.Devices = nil
+	return d
+}
+
+// ToPBDevice creates a Device proto from a DeviceConfig.
+func ToPBDevice(d DeviceConfig) *Device {
+	return &Device{
+		Match:    ToPBDeviceMatch(d.Match),
+		Fallback: d.Fallback,
+		Config:   ToPBConfig(d.Config),
+	}
+}
+
 // RunConfig is the runtime key remap configuration. We do not use the KeymapConfig proto
 // directly because protobuf does not support a map with enum keys.
 type RunConfig struct {
-	FnEnabled        bool                          `json:"fn_enabled"`
-	FnKey            keycode.Code                  `json:"fn_key"`
-	KeyMap           map[keycode.Code]keycode.Code `json:"key_map"`
-	ModKeyMap        map[keycode.Code]keycode.Code `json:"mod_key_map"`
-	ThirdLevelKeyMap map[keycode.Code]keycode.Code `json:"third_level_key_map"`
-	UseLED           keycode.LED                   `json:"use_led"`
-	ThirdLevelKey    []keycode.Code                `json:"third_level_key"`
+	FnEnabled        bool                `json:"fn_enabled"`
+	FnKey            keycode.Code        `json:"fn_key"`
+	KeyMap           Keymap              `json:"key_map"`
+	ModKeyMap        Keymap              `json:"mod_key_map"`
+	ThirdLevelKeyMap Keymap              `json:"third_level_key_map"`
+	UseLED           keycode.LED         `json:"use_led"`
+	ThirdLevelKey    []keycode.Code      `json:"third_level_key"`
+	Layers           []LayerConfig       `json:"layers"`
+	TapHold          []TapHoldConfig     `json:"tap_hold"`
+	Combos           []ComboConfig       `json:"combo"`
+	OutputMode       OutputMode          `json:"output_mode"`
+	KeyboardNames    []string            `json:"keyboard_name"`
+	Devices          []DeviceConfig      `json:"device"`
+	Keyboards        []DeviceMatchConfig `json:"keyboard"`
+	VirtualDevice    VirtualDeviceConfig `json:"virtual_device"`
+	LEDs             []LEDBindingConfig  `json:"led"`
 }
 
 // Clone returns a deep copy of a RunConfig.
 func (cfg RunConfig) Clone() RunConfig {
-	keyMap := make(map[keycode.Code]keycode.Code)
-	for k, v := range cfg.KeyMap {
-		keyMap[k] = v
+	rc := cfg
+	rc.KeyMap = cfg.KeyMap.Clone()
+	rc.ThirdLevelKeyMap = cfg.ThirdLevelKeyMap.Clone()
+	rc.ModKeyMap = cfg.ModKeyMap.Clone()
+	rc.ThirdLevelKey = append([]keycode.Code{}, cfg.ThirdLevelKey...)
+	rc.KeyboardNames = append([]string(nil), cfg.KeyboardNames...)
+	rc.Layers = nil
+	for _, l := range cfg.Layers {
+		rc.Layers = append(rc.Layers, l.Clone())
 	}
-	thirdLevelKeyMap := make(map[keycode.Code]keycode.Code)
-	for k, v := range cfg.ThirdLevelKeyMap {
-		thirdLevelKeyMap[k] = v
+	rc.TapHold = append([]TapHoldConfig(nil), cfg.TapHold...)
+	rc.Combos = nil
+	for _, c := range cfg.Combos {
+		c.Keys = append([]keycode.Code{}, c.Keys...)
+		rc.Combos = append(rc.Combos, c)
 	}
-	modKeyMap := make(map[keycode.Code]keycode.Code)
-	for k, v := range cfg.ThirdLevelKeyMap {
-		modKeyMap[k] = v
+	rc.Keyboards = append([]DeviceMatchConfig(nil), cfg.Keyboards...)
+	rc.LEDs = append([]LEDBindingConfig(nil), cfg.LEDs...)
+	rc.Devices = nil
+	for _, d := range cfg.Devices {
+		d.Config = d.Config.Clone()
+		rc.Devices = append(rc.Devices, d)
 	}
-	rc := cfg
-	rc.KeyMap = keyMap
-	rc.ThirdLevelKeyMap = thirdLevelKeyMap
-	rc.ModKeyMap = modKeyMap
 	return rc
 }
 
@@ -69,11 +575,32 @@ func FromPBConfig(pb *KeymapConfig) RunConfig {
 		KeyMap:           FromPBKeymap(pb.KeyMap),
 		ModKeyMap:        FromPBKeymap(pb.ModKeyMap),
 		ThirdLevelKeyMap: FromPBKeymap(pb.ThirdLevelKeyMap),
+		OutputMode:       pb.OutputMode,
+		KeyboardNames:    append([]string(nil), pb.KeyboardName...),
+		VirtualDevice:    FromPBVirtualDevice(pb.GetVirtualDevice()),
 	}
 	if pb.UseLed != nil {
 		rc.UseLED = pb.GetUseLed()
 	}
 	rc.ThirdLevelKey = append([]keycode.Code{}, pb.GetThirdLevelKey()...)
+	for _, l := range pb.GetLayer() {
+		rc.Layers = append(rc.Layers, FromPBLayer(l))
+	}
+	for _, th := range pb.GetTapHold() {
+		rc.TapHold = append(rc.TapHold, FromPBTapHold(th))
+	}
+	for _, c := range pb.GetCombo() {
+		rc.Combos = append(rc.Combos, FromPBCombo(c))
+	}
+	for _, d := range pb.GetDevice() {
+		rc.Devices = append(rc.Devices, FromPBDevice(d))
+	}
+	for _, m := range pb.GetKeyboard() {
+		rc.Keyboards = append(rc.Keyboards, FromPBDeviceMatch(m))
+	}
+	for _, b := range pb.GetLed() {
+		rc.LEDs = append(rc.LEDs, FromPBLEDBinding(b))
+	}
 	return rc
 }
 
@@ -85,12 +612,33 @@ func ToPBConfig(cfg RunConfig) *KeymapConfig {
 		KeyMap:           ToPBKeymap(cfg.KeyMap),
 		ModKeyMap:        ToPBKeymap(cfg.ModKeyMap),
 		ThirdLevelKeyMap: ToPBKeymap(cfg.ThirdLevelKeyMap),
+		OutputMode:       cfg.OutputMode,
+		KeyboardName:     append([]string(nil), cfg.KeyboardNames...),
+		VirtualDevice:    ToPBVirtualDevice(cfg.VirtualDevice),
 	}
 	if cfg.UseLED <= keycode.LED_MAX {
 		useLED := cfg.UseLED
 		pb.UseLed = &useLED
 	}
 	pb.ThirdLevelKey = append([]keycode.Code{}, cfg.ThirdLevelKey...)
+	for _, l := range cfg.Layers {
+		pb.Layer = append(pb.Layer, ToPBLayer(l))
+	}
+	for _, th := range cfg.TapHold {
+		pb.TapHold = append(pb.TapHold, ToPBTapHold(th))
+	}
+	for _, c := range cfg.Combos {
+		pb.Combo = append(pb.Combo, ToPBCombo(c))
+	}
+	for _, d := range cfg.Devices {
+		pb.Device = append(pb.Device, ToPBDevice(d))
+	}
+	for _, m := range cfg.Keyboards {
+		pb.Keyboard = append(pb.Keyboard, ToPBDeviceMatch(m))
+	}
+	for _, b := range cfg.LEDs {
+		pb.Led = append(pb.Led, ToPBLEDBinding(b))
+	}
 	return &pb
 }
 
@@ -98,9 +646,9 @@ func ToPBConfig(cfg RunConfig) *KeymapConfig {
 func DefaultRunConfig() RunConfig {
 	return RunConfig{
 		FnKey:            keycode.Code_KEY_F13,
-		KeyMap:           defaultFnKeyMap(),
-		ModKeyMap:        defaultModKeyMap(),
-		ThirdLevelKeyMap: defaultThirdLevelKeyMap(),
+		KeyMap:           KeymapOf(defaultFnKeyMap()),
+		ModKeyMap:        KeymapOf(defaultModKeyMap()),
+		ThirdLevelKeyMap: KeymapOf(defaultThirdLevelKeyMap()),
 		ThirdLevelKey:    []keycode.Code{keycode.Code_KEY_LEFTSHIFT, keycode.Code_KEY_RIGHTSHIFT},
 	}
 }
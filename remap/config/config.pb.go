@@ -21,32 +21,978 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// MacroAction is the action of a macro step.
+type MacroAction int32
+
+const (
+	MacroAction_TAP     MacroAction = 0
+	MacroAction_PRESS   MacroAction = 1
+	MacroAction_RELEASE MacroAction = 2
+	MacroAction_DELAY   MacroAction = 3
+)
+
+// Enum value maps for MacroAction.
+var (
+	MacroAction_name = map[int32]string{
+		0: "TAP",
+		1: "PRESS",
+		2: "RELEASE",
+		3: "DELAY",
+	}
+	MacroAction_value = map[string]int32{
+		"TAP":     0,
+		"PRESS":   1,
+		"RELEASE": 2,
+		"DELAY":   3,
+	}
+)
+
+func (x MacroAction) Enum() *MacroAction {
+	p := new(MacroAction)
+	*p = x
+	return p
+}
+
+func (x MacroAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MacroAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_config_proto_enumTypes[0].Descriptor()
+}
+
+func (MacroAction) Type() protoreflect.EnumType {
+	return &file_config_proto_enumTypes[0]
+}
+
+func (x MacroAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MacroAction.Descriptor instead.
+func (MacroAction) EnumDescriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{0}
+}
+
+// LayerMode selects how the activation keys of a layer turn it on and off.
+type LayerMode int32
+
+const (
+	LayerMode_MOMENTARY LayerMode = 0 // Active while an activation key is held.
+	LayerMode_TOGGLE    LayerMode = 1 // Pressing an activation key by itself toggles the layer.
+	LayerMode_ONE_SHOT  LayerMode = 2 // Pressing an activation key by itself arms the layer for the next key press.
+)
+
+// Enum value maps for LayerMode.
+var (
+	LayerMode_name = map[int32]string{
+		0: "MOMENTARY",
+		1: "TOGGLE",
+		2: "ONE_SHOT",
+	}
+	LayerMode_value = map[string]int32{
+		"MOMENTARY": 0,
+		"TOGGLE":    1,
+		"ONE_SHOT":  2,
+	}
+)
+
+func (x LayerMode) Enum() *LayerMode {
+	p := new(LayerMode)
+	*p = x
+	return p
+}
+
+func (x LayerMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LayerMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_config_proto_enumTypes[1].Descriptor()
+}
+
+func (LayerMode) Type() protoreflect.EnumType {
+	return &file_config_proto_enumTypes[1]
+}
+
+func (x LayerMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LayerMode.Descriptor instead.
+func (LayerMode) EnumDescriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{1}
+}
+
+// LEDState is a remapper state that can be shown by an LED.
+type LEDState int32
+
+const (
+	LEDState_FN_LOCK       LEDState = 0 // The FN lock layer is on
+	LEDState_LAYER_ACTIVE  LEDState = 1 // The named layer is active
+	LEDState_MACRO_PLAYING LEDState = 2 // A macro is playing
+	LEDState_CONFIG_ERROR  LEDState = 3 // The last configuration reload failed
+)
+
+// Enum value maps for LEDState.
+var (
+	LEDState_name = map[int32]string{
+		0: "FN_LOCK",
+		1: "LAYER_ACTIVE",
+		2: "MACRO_PLAYING",
+		3: "CONFIG_ERROR",
+	}
+	LEDState_value = map[string]int32{
+		"FN_LOCK":       0,
+		"LAYER_ACTIVE":  1,
+		"MACRO_PLAYING": 2,
+		"CONFIG_ERROR":  3,
+	}
+)
+
+func (x LEDState) Enum() *LEDState {
+	p := new(LEDState)
+	*p = x
+	return p
+}
+
+func (x LEDState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LEDState) Descriptor() protoreflect.EnumDescriptor {
+	return file_config_proto_enumTypes[2].Descriptor()
+}
+
+func (LEDState) Type() protoreflect.EnumType {
+	return &file_config_proto_enumTypes[2]
+}
+
+func (x LEDState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LEDState.Descriptor instead.
+func (LEDState) EnumDescriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{2}
+}
+
+// LEDPattern selects how an LED shows an active state.
+type LEDPattern int32
+
+const (
+	LEDPattern_STEADY LEDPattern = 0
+	LEDPattern_BLINK  LEDPattern = 1 // On for the first half of each period
+)
+
+// Enum value maps for LEDPattern.
+var (
+	LEDPattern_name = map[int32]string{
+		0: "STEADY",
+		1: "BLINK",
+	}
+	LEDPattern_value = map[string]int32{
+		"STEADY": 0,
+		"BLINK":  1,
+	}
+)
+
+func (x LEDPattern) Enum() *LEDPattern {
+	p := new(LEDPattern)
+	*p = x
+	return p
+}
+
+func (x LEDPattern) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LEDPattern) Descriptor() protoreflect.EnumDescriptor {
+	return file_config_proto_enumTypes[3].Descriptor()
+}
+
+func (LEDPattern) Type() protoreflect.EnumType {
+	return &file_config_proto_enumTypes[3]
+}
+
+func (x LEDPattern) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LEDPattern.Descriptor instead.
+func (LEDPattern) EnumDescriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{3}
+}
+
+// OutputMode selects the virtual keyboards of a remapper with more than one input device.
+type OutputMode int32
+
+const (
+	OutputMode_SHARED_OUTPUT     OutputMode = 0 // One virtual keyboard for all input devices
+	OutputMode_PER_DEVICE_OUTPUT OutputMode = 1 // One virtual keyboard per input device
+)
+
+// Enum value maps for OutputMode.
+var (
+	OutputMode_name = map[int32]string{
+		0: "SHARED_OUTPUT",
+		1: "PER_DEVICE_OUTPUT",
+	}
+	OutputMode_value = map[string]int32{
+		"SHARED_OUTPUT":     0,
+		"PER_DEVICE_OUTPUT": 1,
+	}
+)
+
+func (x OutputMode) Enum() *OutputMode {
+	p := new(OutputMode)
+	*p = x
+	return p
+}
+
+func (x OutputMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OutputMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_config_proto_enumTypes[4].Descriptor()
+}
+
+func (OutputMode) Type() protoreflect.EnumType {
+	return &file_config_proto_enumTypes[4]
+}
+
+func (x OutputMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OutputMode.Descriptor instead.
+func (OutputMode) EnumDescriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{4}
+}
+
+type MacroStep struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Action  MacroAction  `protobuf:"varint,1,opt,name=action,proto3,enum=config.MacroAction" json:"action,omitempty"`
+	Key     keycode.Code `protobuf:"varint,2,opt,name=key,proto3,enum=keycode.Code" json:"key,omitempty"`
+	DelayMs uint32       `protobuf:"varint,3,opt,name=delay_ms,json=delayMs,proto3" json:"delay_ms,omitempty"` // Wait time of a DELAY step
+}
+
+func (x *MacroStep) Reset() {
+	*x = MacroStep{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MacroStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MacroStep) ProtoMessage() {}
+
+func (x *MacroStep) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MacroStep.ProtoReflect.Descriptor instead.
+func (*MacroStep) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MacroStep) GetAction() MacroAction {
+	if x != nil {
+		return x.Action
+	}
+	return MacroAction_TAP
+}
+
+func (x *MacroStep) GetKey() keycode.Code {
+	if x != nil {
+		return x.Key
+	}
+	return keycode.Code(0)
+}
+
+func (x *MacroStep) GetDelayMs() uint32 {
+	if x != nil {
+		return x.DelayMs
+	}
+	return 0
+}
+
 type KeymapEntry struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	From keycode.Code `protobuf:"varint,1,opt,name=from,proto3,enum=keycode.Code" json:"from,omitempty"`
-	To   keycode.Code `protobuf:"varint,2,opt,name=to,proto3,enum=keycode.Code" json:"to,omitempty"`
+	From keycode.Code `protobuf:"varint,1,opt,name=from,proto3,enum=keycode.Code" json:"from,omitempty"`
+	To   keycode.Code `protobuf:"varint,2,opt,name=to,proto3,enum=keycode.Code" json:"to,omitempty"`
+	// Reserved tags here for future non-repeating fields.
+	Macro       []*MacroStep   `protobuf:"bytes,19,rep,name=macro,proto3" json:"macro,omitempty"`                                                          // Plays the steps instead of sending the to key
+	AddMod      []keycode.Code `protobuf:"varint,20,rep,packed,name=add_mod,json=addMod,proto3,enum=keycode.Code" json:"add_mod,omitempty"`                // Modifiers pressed while the to key is held
+	SuppressMod []keycode.Code `protobuf:"varint,21,rep,packed,name=suppress_mod,json=suppressMod,proto3,enum=keycode.Code" json:"suppress_mod,omitempty"` // Modifiers released while the to key is held
+	RequireMod  []keycode.Code `protobuf:"varint,22,rep,packed,name=require_mod,json=requireMod,proto3,enum=keycode.Code" json:"require_mod,omitempty"`    // Matches only while all these modifiers are held
+	ForbidMod   []keycode.Code `protobuf:"varint,23,rep,packed,name=forbid_mod,json=forbidMod,proto3,enum=keycode.Code" json:"forbid_mod,omitempty"`       // Matches only while none of these modifiers is held
+}
+
+func (x *KeymapEntry) Reset() {
+	*x = KeymapEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_config_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KeymapEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeymapEntry) ProtoMessage() {}
+
+func (x *KeymapEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeymapEntry.ProtoReflect.Descriptor instead.
+func (*KeymapEntry) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *KeymapEntry) GetFrom() keycode.Code {
+	if x != nil {
+		return x.From
+	}
+	return keycode.Code(0)
+}
+
+func (x *KeymapEntry) GetTo() keycode.Code {
+	if x != nil {
+		return x.To
+	}
+	return keycode.Code(0)
+}
+
+func (x *KeymapEntry) GetMacro() []*MacroStep {
+	if x != nil {
+		return x.Macro
+	}
+	return nil
+}
+
+func (x *KeymapEntry) GetAddMod() []keycode.Code {
+	if x != nil {
+		return x.AddMod
+	}
+	return nil
+}
+
+func (x *KeymapEntry) GetSuppressMod() []keycode.Code {
+	if x != nil {
+		return x.SuppressMod
+	}
+	return nil
+}
+
+func (x *KeymapEntry) GetRequireMod() []keycode.Code {
+	if x != nil {
+		return x.RequireMod
+	}
+	return nil
+}
+
+func (x *KeymapEntry) GetForbidMod() []keycode.Code {
+	if x != nil {
+		return x.ForbidMod
+	}
+	return nil
+}
+
+type Layer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string       `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Mode        LayerMode    `protobuf:"varint,2,opt,name=mode,proto3,enum=config.LayerMode" json:"mode,omitempty"`
+	Enabled     bool         `protobuf:"varint,3,opt,name=enabled,proto3" json:"enabled,omitempty"`                                  // Initial state of TOGGLE and ONE_SHOT layers
+	Transparent bool         `protobuf:"varint,4,opt,name=transparent,proto3" json:"transparent,omitempty"`                          // Unmapped keys fall through to lower layers
+	InvertLayer string       `protobuf:"bytes,5,opt,name=invert_layer,json=invertLayer,proto3" json:"invert_layer,omitempty"`        // Inverts the named layer while this layer is active
+	ReleaseKeys bool         `protobuf:"varint,6,opt,name=release_keys,json=releaseKeys,proto3" json:"release_keys,omitempty"`       // Suppresses held activation keys while a mapped key is held
+	SendKey     keycode.Code `protobuf:"varint,7,opt,name=send_key,json=sendKey,proto3,enum=keycode.Code" json:"send_key,omitempty"` // Replaces activation key events, KEY_RESERVED forwards them
+	// Reserved tags here for future non-repeating fields.
+	Key          []keycode.Code `protobuf:"varint,19,rep,packed,name=key,proto3,enum=keycode.Code" json:"key,omitempty"`             // Activation keys
+	RequireLayer []string       `protobuf:"bytes,20,rep,name=require_layer,json=requireLayer,proto3" json:"require_layer,omitempty"` // Active only while all these layers are active
+	KeyMap       []*KeymapEntry `protobuf:"bytes,21,rep,name=key_map,json=keyMap,proto3" json:"key_map,omitempty"`
+}
+
+func (x *Layer) Reset() {
+	*x = Layer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_config_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Layer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Layer) ProtoMessage() {}
+
+func (x *Layer) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Layer.ProtoReflect.Descriptor instead.
+func (*Layer) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Layer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Layer) GetMode() LayerMode {
+	if x != nil {
+		return x.Mode
+	}
+	return LayerMode_MOMENTARY
+}
+
+func (x *Layer) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *Layer) GetTransparent() bool {
+	if x != nil {
+		return x.Transparent
+	}
+	return false
+}
+
+func (x *Layer) GetInvertLayer() string {
+	if x != nil {
+		return x.InvertLayer
+	}
+	return ""
+}
+
+func (x *Layer) GetReleaseKeys() bool {
+	if x != nil {
+		return x.ReleaseKeys
+	}
+	return false
+}
+
+func (x *Layer) GetSendKey() keycode.Code {
+	if x != nil {
+		return x.SendKey
+	}
+	return keycode.Code(0)
+}
+
+func (x *Layer) GetKey() []keycode.Code {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *Layer) GetRequireLayer() []string {
+	if x != nil {
+		return x.RequireLayer
+	}
+	return nil
+}
+
+func (x *Layer) GetKeyMap() []*KeymapEntry {
+	if x != nil {
+		return x.KeyMap
+	}
+	return nil
+}
+
+// TapHold makes a key send one key when tapped and another key or a layer when held.
+type TapHold struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key            keycode.Code `protobuf:"varint,1,opt,name=key,proto3,enum=keycode.Code" json:"key,omitempty"`
+	Tap            keycode.Code `protobuf:"varint,2,opt,name=tap,proto3,enum=keycode.Code" json:"tap,omitempty"`                           // Sent when tapped, KEY_RESERVED sends the key itself
+	Hold           keycode.Code `protobuf:"varint,3,opt,name=hold,proto3,enum=keycode.Code" json:"hold,omitempty"`                         // Held down while the key is held
+	HoldLayer      string       `protobuf:"bytes,4,opt,name=hold_layer,json=holdLayer,proto3" json:"hold_layer,omitempty"`                 // Active while the key is held
+	TimeoutMs      uint32       `protobuf:"varint,5,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`                // Hold timeout, 0 uses the default timeout
+	PermissiveHold bool         `protobuf:"varint,6,opt,name=permissive_hold,json=permissiveHold,proto3" json:"permissive_hold,omitempty"` // Hold when another key is pressed and released while the key is held
+	QuickTapMs     uint32       `protobuf:"varint,7,opt,name=quick_tap_ms,json=quickTapMs,proto3" json:"quick_tap_ms,omitempty"`           // Holds the tap key if the key is pressed again within this time after a tap
+}
+
+func (x *TapHold) Reset() {
+	*x = TapHold{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_config_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TapHold) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TapHold) ProtoMessage() {}
+
+func (x *TapHold) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TapHold.ProtoReflect.Descriptor instead.
+func (*TapHold) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TapHold) GetKey() keycode.Code {
+	if x != nil {
+		return x.Key
+	}
+	return keycode.Code(0)
+}
+
+func (x *TapHold) GetTap() keycode.Code {
+	if x != nil {
+		return x.Tap
+	}
+	return keycode.Code(0)
+}
+
+func (x *TapHold) GetHold() keycode.Code {
+	if x != nil {
+		return x.Hold
+	}
+	return keycode.Code(0)
+}
+
+func (x *TapHold) GetHoldLayer() string {
+	if x != nil {
+		return x.HoldLayer
+	}
+	return ""
+}
+
+func (x *TapHold) GetTimeoutMs() uint32 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *TapHold) GetPermissiveHold() bool {
+	if x != nil {
+		return x.PermissiveHold
+	}
+	return false
+}
+
+func (x *TapHold) GetQuickTapMs() uint32 {
+	if x != nil {
+		return x.QuickTapMs
+	}
+	return 0
+}
+
+// Combo sends a key when all its keys are pressed at about the same time.
+type Combo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	To        keycode.Code `protobuf:"varint,1,opt,name=to,proto3,enum=keycode.Code" json:"to,omitempty"`
+	TimeoutMs uint32       `protobuf:"varint,2,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"` // Time to press all keys, 0 uses the default timeout
+	// Reserved tags here for future non-repeating fields.
+	Key []keycode.Code `protobuf:"varint,19,rep,packed,name=key,proto3,enum=keycode.Code" json:"key,omitempty"`
+}
+
+func (x *Combo) Reset() {
+	*x = Combo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_config_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Combo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Combo) ProtoMessage() {}
+
+func (x *Combo) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Combo.ProtoReflect.Descriptor instead.
+func (*Combo) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Combo) GetTo() keycode.Code {
+	if x != nil {
+		return x.To
+	}
+	return keycode.Code(0)
+}
+
+func (x *Combo) GetTimeoutMs() uint32 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *Combo) GetKey() []keycode.Code {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+// LEDBinding lights an LED while a remapper state is active.
+type LEDBinding struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Led      keycode.LED `protobuf:"varint,1,opt,name=led,proto3,enum=keycode.LED" json:"led,omitempty"`
+	State    LEDState    `protobuf:"varint,2,opt,name=state,proto3,enum=config.LEDState" json:"state,omitempty"`
+	Layer    string      `protobuf:"bytes,3,opt,name=layer,proto3" json:"layer,omitempty"` // Layer of a LAYER_ACTIVE state
+	Pattern  LEDPattern  `protobuf:"varint,4,opt,name=pattern,proto3,enum=config.LEDPattern" json:"pattern,omitempty"`
+	PeriodMs uint32      `protobuf:"varint,5,opt,name=period_ms,json=periodMs,proto3" json:"period_ms,omitempty"` // Blink period, 0 uses the default period
+}
+
+func (x *LEDBinding) Reset() {
+	*x = LEDBinding{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_config_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LEDBinding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LEDBinding) ProtoMessage() {}
+
+func (x *LEDBinding) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LEDBinding.ProtoReflect.Descriptor instead.
+func (*LEDBinding) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LEDBinding) GetLed() keycode.LED {
+	if x != nil {
+		return x.Led
+	}
+	return keycode.LED(0)
+}
+
+func (x *LEDBinding) GetState() LEDState {
+	if x != nil {
+		return x.State
+	}
+	return LEDState_FN_LOCK
+}
+
+func (x *LEDBinding) GetLayer() string {
+	if x != nil {
+		return x.Layer
+	}
+	return ""
+}
+
+func (x *LEDBinding) GetPattern() LEDPattern {
+	if x != nil {
+		return x.Pattern
+	}
+	return LEDPattern_STEADY
+}
+
+func (x *LEDBinding) GetPeriodMs() uint32 {
+	if x != nil {
+		return x.PeriodMs
+	}
+	return 0
+}
+
+type KeymapConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FnEnabled     bool           `protobuf:"varint,1,opt,name=fn_enabled,json=fnEnabled,proto3" json:"fn_enabled,omitempty"`
+	FnKey         keycode.Code   `protobuf:"varint,2,opt,name=fn_key,json=fnKey,proto3,enum=keycode.Code" json:"fn_key,omitempty"`
+	UseLed        *keycode.LED   `protobuf:"varint,3,opt,name=use_led,json=useLed,proto3,enum=keycode.LED,oneof" json:"use_led,omitempty"`
+	OutputMode    OutputMode     `protobuf:"varint,4,opt,name=output_mode,json=outputMode,proto3,enum=config.OutputMode" json:"output_mode,omitempty"`
+	VirtualDevice *VirtualDevice `protobuf:"bytes,5,opt,name=virtual_device,json=virtualDevice,proto3" json:"virtual_device,omitempty"` // Identity of the virtual keyboards
+	// Reserved tags here for future non-repeating fields.
+	ThirdLevelKey    []keycode.Code `protobuf:"varint,19,rep,packed,name=third_level_key,json=thirdLevelKey,proto3,enum=keycode.Code" json:"third_level_key,omitempty"` // FN+3rd_level+key
+	KeyMap           []*KeymapEntry `protobuf:"bytes,20,rep,name=key_map,json=keyMap,proto3" json:"key_map,omitempty"`                                                  // FN locked
+	ModKeyMap        []*KeymapEntry `protobuf:"bytes,21,rep,name=mod_key_map,json=modKeyMap,proto3" json:"mod_key_map,omitempty"`                                       // FN+key
+	ThirdLevelKeyMap []*KeymapEntry `protobuf:"bytes,22,rep,name=third_level_key_map,json=thirdLevelKeyMap,proto3" json:"third_level_key_map,omitempty"`                // FN+3rd_level+key
+	Layer            []*Layer       `protobuf:"bytes,23,rep,name=layer,proto3" json:"layer,omitempty"`                                                                  // Replaces the FN key maps above when set, the last layer has the highest priority
+	TapHold          []*TapHold     `protobuf:"bytes,24,rep,name=tap_hold,json=tapHold,proto3" json:"tap_hold,omitempty"`                                               // Dual-role keys
+	Combo            []*Combo       `protobuf:"bytes,25,rep,name=combo,proto3" json:"combo,omitempty"`                                                                  // Chords of simultaneous key presses
+	KeyboardName     []string       `protobuf:"bytes,26,rep,name=keyboard_name,json=keyboardName,proto3" json:"keyboard_name,omitempty"`                                // Grabs all keyboards with a name that contains any of these sub-strings
+	Device           []*Device      `protobuf:"bytes,27,rep,name=device,proto3" json:"device,omitempty"`                                                                // Per-device key maps, the first matching section is used
+	Keyboard         []*DeviceMatch `protobuf:"bytes,28,rep,name=keyboard,proto3" json:"keyboard,omitempty"`                                                            // Grabs all keyboards that match any of these
+	Led              []*LEDBinding  `protobuf:"bytes,29,rep,name=led,proto3" json:"led,omitempty"`                                                                      // LEDs that show remapper states, in addition to use_led
+}
+
+func (x *KeymapConfig) Reset() {
+	*x = KeymapConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_config_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KeymapConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeymapConfig) ProtoMessage() {}
+
+func (x *KeymapConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeymapConfig.ProtoReflect.Descriptor instead.
+func (*KeymapConfig) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *KeymapConfig) GetFnEnabled() bool {
+	if x != nil {
+		return x.FnEnabled
+	}
+	return false
+}
+
+func (x *KeymapConfig) GetFnKey() keycode.Code {
+	if x != nil {
+		return x.FnKey
+	}
+	return keycode.Code(0)
+}
+
+func (x *KeymapConfig) GetUseLed() keycode.LED {
+	if x != nil && x.UseLed != nil {
+		return *x.UseLed
+	}
+	return keycode.LED(0)
+}
+
+func (x *KeymapConfig) GetOutputMode() OutputMode {
+	if x != nil {
+		return x.OutputMode
+	}
+	return OutputMode_SHARED_OUTPUT
+}
+
+func (x *KeymapConfig) GetVirtualDevice() *VirtualDevice {
+	if x != nil {
+		return x.VirtualDevice
+	}
+	return nil
+}
+
+func (x *KeymapConfig) GetThirdLevelKey() []keycode.Code {
+	if x != nil {
+		return x.ThirdLevelKey
+	}
+	return nil
+}
+
+func (x *KeymapConfig) GetKeyMap() []*KeymapEntry {
+	if x != nil {
+		return x.KeyMap
+	}
+	return nil
+}
+
+func (x *KeymapConfig) GetModKeyMap() []*KeymapEntry {
+	if x != nil {
+		return x.ModKeyMap
+	}
+	return nil
+}
+
+func (x *KeymapConfig) GetThirdLevelKeyMap() []*KeymapEntry {
+	if x != nil {
+		return x.ThirdLevelKeyMap
+	}
+	return nil
+}
+
+func (x *KeymapConfig) GetLayer() []*Layer {
+	if x != nil {
+		return x.Layer
+	}
+	return nil
+}
+
+func (x *KeymapConfig) GetTapHold() []*TapHold {
+	if x != nil {
+		return x.TapHold
+	}
+	return nil
+}
+
+func (x *KeymapConfig) GetCombo() []*Combo {
+	if x != nil {
+		return x.Combo
+	}
+	return nil
+}
+
+func (x *KeymapConfig) GetKeyboardName() []string {
+	if x != nil {
+		return x.KeyboardName
+	}
+	return nil
+}
+
+func (x *KeymapConfig) GetDevice() []*Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+func (x *KeymapConfig) GetKeyboard() []*DeviceMatch {
+	if x != nil {
+		return x.Keyboard
+	}
+	return nil
+}
+
+func (x *KeymapConfig) GetLed() []*LEDBinding {
+	if x != nil {
+		return x.Led
+	}
+	return nil
+}
+
+// DeviceMatch selects input devices. All the set fields must match.
+// The virtual keyboards of the remapper never match.
+type DeviceMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      string      `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                            // Name sub-string
+	NameRegex string      `protobuf:"bytes,2,opt,name=name_regex,json=nameRegex,proto3" json:"name_regex,omitempty"` // Name regular expression
+	Id        string      `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`                                // Vendor and product IDs in hexadecimal "vendor:product" format, e.g. "17ef:6047"
+	Bus       keycode.Bus `protobuf:"varint,4,opt,name=bus,proto3,enum=keycode.Bus" json:"bus,omitempty"`
+	Phys      string      `protobuf:"bytes,5,opt,name=phys,proto3" json:"phys,omitempty"`                          // Physical path sub-string, e.g. "isa0060/serio0"
+	Link      string      `protobuf:"bytes,6,opt,name=link,proto3" json:"link,omitempty"`                          // Symlink to the device node, e.g. "/dev/input/by-path/platform-i8042-serio-0-event-kbd"
+	MinScore  int32       `protobuf:"varint,7,opt,name=min_score,json=minScore,proto3" json:"min_score,omitempty"` // Minimum number of typewriter keys, a full keyboard has 57
+	Virtual   bool        `protobuf:"varint,8,opt,name=virtual,proto3" json:"virtual,omitempty"`                   // Also matches virtual devices, e.g. the keyboards of other remappers
 }
 
-func (x *KeymapEntry) Reset() {
-	*x = KeymapEntry{}
+func (x *DeviceMatch) Reset() {
+	*x = DeviceMatch{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_config_proto_msgTypes[0]
+		mi := &file_config_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *KeymapEntry) String() string {
+func (x *DeviceMatch) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KeymapEntry) ProtoMessage() {}
+func (*DeviceMatch) ProtoMessage() {}
 
-func (x *KeymapEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_config_proto_msgTypes[0]
+func (x *DeviceMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -57,57 +1003,99 @@ func (x *KeymapEntry) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KeymapEntry.ProtoReflect.Descriptor instead.
-func (*KeymapEntry) Descriptor() ([]byte, []int) {
-	return file_config_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use DeviceMatch.ProtoReflect.Descriptor instead.
+func (*DeviceMatch) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *KeymapEntry) GetFrom() keycode.Code {
+func (x *DeviceMatch) GetName() string {
 	if x != nil {
-		return x.From
+		return x.Name
 	}
-	return keycode.Code(0)
+	return ""
 }
 
-func (x *KeymapEntry) GetTo() keycode.Code {
+func (x *DeviceMatch) GetNameRegex() string {
 	if x != nil {
-		return x.To
+		return x.NameRegex
 	}
-	return keycode.Code(0)
+	return ""
 }
 
-type KeymapConfig struct {
+func (x *DeviceMatch) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeviceMatch) GetBus() keycode.Bus {
+	if x != nil {
+		return x.Bus
+	}
+	return keycode.Bus(0)
+}
+
+func (x *DeviceMatch) GetPhys() string {
+	if x != nil {
+		return x.Phys
+	}
+	return ""
+}
+
+func (x *DeviceMatch) GetLink() string {
+	if x != nil {
+		return x.Link
+	}
+	return ""
+}
+
+func (x *DeviceMatch) GetMinScore() int32 {
+	if x != nil {
+		return x.MinScore
+	}
+	return 0
+}
+
+func (x *DeviceMatch) GetVirtual() bool {
+	if x != nil {
+		return x.Virtual
+	}
+	return false
+}
+
+// VirtualDevice is the identity of the virtual keyboards that desktop environments and udev rules see.
+type VirtualDevice struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	FnEnabled bool         `protobuf:"varint,1,opt,name=fn_enabled,json=fnEnabled,proto3" json:"fn_enabled,omitempty"`
-	FnKey     keycode.Code `protobuf:"varint,2,opt,name=fn_key,json=fnKey,proto3,enum=keycode.Code" json:"fn_key,omitempty"`
-	UseLed    *keycode.LED `protobuf:"varint,3,opt,name=use_led,json=useLed,proto3,enum=keycode.LED,oneof" json:"use_led,omitempty"`
-	// Reserved tags here for future non-repeating fields.
-	ThirdLevelKey    []keycode.Code `protobuf:"varint,19,rep,packed,name=third_level_key,json=thirdLevelKey,proto3,enum=keycode.Code" json:"third_level_key,omitempty"` // FN+3rd_level+key
-	KeyMap           []*KeymapEntry `protobuf:"bytes,20,rep,name=key_map,json=keyMap,proto3" json:"key_map,omitempty"`                                                  // FN locked
-	ModKeyMap        []*KeymapEntry `protobuf:"bytes,21,rep,name=mod_key_map,json=modKeyMap,proto3" json:"mod_key_map,omitempty"`                                       // FN+key
-	ThirdLevelKeyMap []*KeymapEntry `protobuf:"bytes,22,rep,name=third_level_key_map,json=thirdLevelKeyMap,proto3" json:"third_level_key_map,omitempty"`                // FN+3rd_level+key
+	Name       string      `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                // Default "Chromebook keyboard remap"
+	Id         string      `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`                                    // Vendor and product IDs in hexadecimal "vendor:product" format, default "0001:0001"
+	Bus        keycode.Bus `protobuf:"varint,3,opt,name=bus,proto3,enum=keycode.Bus" json:"bus,omitempty"`                // Default BUS_USB
+	Version    uint32      `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`                         // Default 9999
+	Phys       string      `protobuf:"bytes,5,opt,name=phys,proto3" json:"phys,omitempty"`                                // Physical path, default none
+	CopySource bool        `protobuf:"varint,6,opt,name=copy_source,json=copySource,proto3" json:"copy_source,omitempty"` // Copies the identity of the input device, or the first one with a shared virtual keyboard
+	Suffix     string      `protobuf:"bytes,7,opt,name=suffix,proto3" json:"suffix,omitempty"`                            // Appended to the copied name
 }
 
-func (x *KeymapConfig) Reset() {
-	*x = KeymapConfig{}
+func (x *VirtualDevice) Reset() {
+	*x = VirtualDevice{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_config_proto_msgTypes[1]
+		mi := &file_config_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *KeymapConfig) String() string {
+func (x *VirtualDevice) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KeymapConfig) ProtoMessage() {}
+func (*VirtualDevice) ProtoMessage() {}
 
-func (x *KeymapConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_config_proto_msgTypes[1]
+func (x *VirtualDevice) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -118,56 +1106,161 @@ func (x *KeymapConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KeymapConfig.ProtoReflect.Descriptor instead.
-func (*KeymapConfig) Descriptor() ([]byte, []int) {
-	return file_config_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use VirtualDevice.ProtoReflect.Descriptor instead.
+func (*VirtualDevice) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *KeymapConfig) GetFnEnabled() bool {
+func (x *VirtualDevice) GetName() string {
 	if x != nil {
-		return x.FnEnabled
+		return x.Name
+	}
+	return ""
+}
+
+func (x *VirtualDevice) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *VirtualDevice) GetBus() keycode.Bus {
+	if x != nil {
+		return x.Bus
+	}
+	return keycode.Bus(0)
+}
+
+func (x *VirtualDevice) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *VirtualDevice) GetPhys() string {
+	if x != nil {
+		return x.Phys
+	}
+	return ""
+}
+
+func (x *VirtualDevice) GetCopySource() bool {
+	if x != nil {
+		return x.CopySource
 	}
 	return false
 }
 
-func (x *KeymapConfig) GetFnKey() keycode.Code {
+func (x *VirtualDevice) GetSuffix() string {
 	if x != nil {
-		return x.FnKey
+		return x.Suffix
 	}
-	return keycode.Code(0)
+	return ""
 }
 
-func (x *KeymapConfig) GetUseLed() keycode.LED {
-	if x != nil && x.UseLed != nil {
-		return *x.UseLed
+// Device selects the key maps of input devices. All the set fields must match.
+// The name, vendor, product, bus and phys fields are shorthands for the same fields of match.
+type Device struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // Name sub-string
+	Vendor   uint32        `protobuf:"varint,2,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	Product  uint32        `protobuf:"varint,3,opt,name=product,proto3" json:"product,omitempty"`
+	Bus      keycode.Bus   `protobuf:"varint,4,opt,name=bus,proto3,enum=keycode.Bus" json:"bus,omitempty"`
+	Phys     string        `protobuf:"bytes,5,opt,name=phys,proto3" json:"phys,omitempty"`          // Physical path sub-string, e.g. "isa0060/serio0"
+	Fallback bool          `protobuf:"varint,6,opt,name=fallback,proto3" json:"fallback,omitempty"` // Used by devices that match no other section instead of the top-level key maps
+	Config   *KeymapConfig `protobuf:"bytes,7,opt,name=config,proto3" json:"config,omitempty"`      // Key maps of the matched devices, output_mode, keyboard_name, keyboard and device are ignored
+	Match    *DeviceMatch  `protobuf:"bytes,8,opt,name=match,proto3" json:"match,omitempty"`
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_config_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return keycode.LED(0)
 }
 
-func (x *KeymapConfig) GetThirdLevelKey() []keycode.Code {
+func (x *Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Device) ProtoMessage() {}
+
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Device) GetName() string {
 	if x != nil {
-		return x.ThirdLevelKey
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-func (x *KeymapConfig) GetKeyMap() []*KeymapEntry {
+func (x *Device) GetVendor() uint32 {
 	if x != nil {
-		return x.KeyMap
+		return x.Vendor
 	}
-	return nil
+	return 0
 }
 
-func (x *KeymapConfig) GetModKeyMap() []*KeymapEntry {
+func (x *Device) GetProduct() uint32 {
 	if x != nil {
-		return x.ModKeyMap
+		return x.Product
+	}
+	return 0
+}
+
+func (x *Device) GetBus() keycode.Bus {
+	if x != nil {
+		return x.Bus
+	}
+	return keycode.Bus(0)
+}
+
+func (x *Device) GetPhys() string {
+	if x != nil {
+		return x.Phys
+	}
+	return ""
+}
+
+func (x *Device) GetFallback() bool {
+	if x != nil {
+		return x.Fallback
+	}
+	return false
+}
+
+func (x *Device) GetConfig() *KeymapConfig {
+	if x != nil {
+		return x.Config
 	}
 	return nil
 }
 
-func (x *KeymapConfig) GetThirdLevelKeyMap() []*KeymapEntry {
+func (x *Device) GetMatch() *DeviceMatch {
 	if x != nil {
-		return x.ThirdLevelKeyMap
+		return x.Match
 	}
 	return nil
 }
@@ -178,38 +1271,199 @@ var file_config_proto_rawDesc = []byte{
 	0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
 	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x1a, 0x1b, 0x65, 0x76, 0x64, 0x65, 0x76, 0x2f, 0x6b, 0x65,
 	0x79, 0x63, 0x6f, 0x64, 0x65, 0x2f, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x22, 0x4f, 0x0a, 0x0b, 0x4b, 0x65, 0x79, 0x6d, 0x61, 0x70, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x12, 0x21, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x6f, 0x74, 0x6f, 0x22, 0x74, 0x0a, 0x09, 0x4d, 0x61, 0x63, 0x72, 0x6f, 0x53, 0x74, 0x65, 0x70,
+	0x12, 0x2b, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x13, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4d, 0x61, 0x63, 0x72, 0x6f, 0x41,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x6b, 0x65, 0x79,
+	0x63, 0x6f, 0x64, 0x65, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x19,
+	0x0a, 0x08, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x5f, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x07, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x4d, 0x73, 0x22, 0xb0, 0x02, 0x0a, 0x0b, 0x4b, 0x65,
+	0x79, 0x6d, 0x61, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x21, 0x0a, 0x04, 0x66, 0x72, 0x6f,
+	0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64,
+	0x65, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x1d, 0x0a, 0x02,
+	0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x6b, 0x65, 0x79, 0x63, 0x6f,
+	0x64, 0x65, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x27, 0x0a, 0x05, 0x6d,
+	0x61, 0x63, 0x72, 0x6f, 0x18, 0x13, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x2e, 0x4d, 0x61, 0x63, 0x72, 0x6f, 0x53, 0x74, 0x65, 0x70, 0x52, 0x05, 0x6d,
+	0x61, 0x63, 0x72, 0x6f, 0x12, 0x26, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x5f, 0x6d, 0x6f, 0x64, 0x18,
+	0x14, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e,
+	0x43, 0x6f, 0x64, 0x65, 0x52, 0x06, 0x61, 0x64, 0x64, 0x4d, 0x6f, 0x64, 0x12, 0x30, 0x0a, 0x0c,
+	0x73, 0x75, 0x70, 0x70, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x6d, 0x6f, 0x64, 0x18, 0x15, 0x20, 0x03,
+	0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x43, 0x6f, 0x64,
+	0x65, 0x52, 0x0b, 0x73, 0x75, 0x70, 0x70, 0x72, 0x65, 0x73, 0x73, 0x4d, 0x6f, 0x64, 0x12, 0x2e,
+	0x0a, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x18, 0x16, 0x20,
+	0x03, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x43, 0x6f,
+	0x64, 0x65, 0x52, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x4d, 0x6f, 0x64, 0x12, 0x2c,
+	0x0a, 0x0a, 0x66, 0x6f, 0x72, 0x62, 0x69, 0x64, 0x5f, 0x6d, 0x6f, 0x64, 0x18, 0x17, 0x20, 0x03,
+	0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x43, 0x6f, 0x64,
+	0x65, 0x52, 0x09, 0x66, 0x6f, 0x72, 0x62, 0x69, 0x64, 0x4d, 0x6f, 0x64, 0x22, 0xe2, 0x02, 0x0a,
+	0x05, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x25, 0x0a, 0x04, 0x6d, 0x6f,
+	0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x2e, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x12, 0x21, 0x0a,
+	0x0c, 0x69, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x5f, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x4c, 0x61, 0x79, 0x65, 0x72,
+	0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x73,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x72, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x4b,
+	0x65, 0x79, 0x73, 0x12, 0x28, 0x0a, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x5f, 0x6b, 0x65, 0x79, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e,
+	0x43, 0x6f, 0x64, 0x65, 0x52, 0x07, 0x73, 0x65, 0x6e, 0x64, 0x4b, 0x65, 0x79, 0x12, 0x1f, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x13, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x6b, 0x65, 0x79,
+	0x63, 0x6f, 0x64, 0x65, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x23,
+	0x0a, 0x0d, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x5f, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18,
+	0x14, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x4c, 0x61,
+	0x79, 0x65, 0x72, 0x12, 0x2c, 0x0a, 0x07, 0x6b, 0x65, 0x79, 0x5f, 0x6d, 0x61, 0x70, 0x18, 0x15,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4b, 0x65,
+	0x79, 0x6d, 0x61, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6b, 0x65, 0x79, 0x4d, 0x61,
+	0x70, 0x22, 0xf7, 0x01, 0x0a, 0x07, 0x54, 0x61, 0x70, 0x48, 0x6f, 0x6c, 0x64, 0x12, 0x1f, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x6b, 0x65, 0x79,
+	0x63, 0x6f, 0x64, 0x65, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x1f,
+	0x0a, 0x03, 0x74, 0x61, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x6b, 0x65,
+	0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x03, 0x74, 0x61, 0x70, 0x12,
+	0x21, 0x0a, 0x04, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e,
+	0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x68, 0x6f,
+	0x6c, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x6f, 0x6c, 0x64, 0x5f, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x68, 0x6f, 0x6c, 0x64, 0x4c, 0x61, 0x79, 0x65,
+	0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x6d, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x4d, 0x73,
+	0x12, 0x27, 0x0a, 0x0f, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x76, 0x65, 0x5f, 0x68,
+	0x6f, 0x6c, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x70, 0x65, 0x72, 0x6d, 0x69,
+	0x73, 0x73, 0x69, 0x76, 0x65, 0x48, 0x6f, 0x6c, 0x64, 0x12, 0x20, 0x0a, 0x0c, 0x71, 0x75, 0x69,
+	0x63, 0x6b, 0x5f, 0x74, 0x61, 0x70, 0x5f, 0x6d, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0a, 0x71, 0x75, 0x69, 0x63, 0x6b, 0x54, 0x61, 0x70, 0x4d, 0x73, 0x22, 0x66, 0x0a, 0x05, 0x43,
+	0x6f, 0x6d, 0x62, 0x6f, 0x12, 0x1d, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
 	0x32, 0x0d, 0x2e, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52,
-	0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x1d, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x0d, 0x2e, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x43, 0x6f, 0x64, 0x65,
-	0x52, 0x02, 0x74, 0x6f, 0x22, 0xe9, 0x02, 0x0a, 0x0c, 0x4b, 0x65, 0x79, 0x6d, 0x61, 0x70, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x6e, 0x5f, 0x65, 0x6e, 0x61, 0x62,
-	0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x66, 0x6e, 0x45, 0x6e, 0x61,
-	0x62, 0x6c, 0x65, 0x64, 0x12, 0x24, 0x0a, 0x06, 0x66, 0x6e, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x43,
-	0x6f, 0x64, 0x65, 0x52, 0x05, 0x66, 0x6e, 0x4b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x07, 0x75, 0x73,
-	0x65, 0x5f, 0x6c, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x6b, 0x65,
-	0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x4c, 0x45, 0x44, 0x48, 0x00, 0x52, 0x06, 0x75, 0x73, 0x65,
-	0x4c, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x35, 0x0a, 0x0f, 0x74, 0x68, 0x69, 0x72, 0x64, 0x5f,
-	0x6c, 0x65, 0x76, 0x65, 0x6c, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x13, 0x20, 0x03, 0x28, 0x0e, 0x32,
-	0x0d, 0x2e, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x0d,
-	0x74, 0x68, 0x69, 0x72, 0x64, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x4b, 0x65, 0x79, 0x12, 0x2c, 0x0a,
-	0x07, 0x6b, 0x65, 0x79, 0x5f, 0x6d, 0x61, 0x70, 0x18, 0x14, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13,
-	0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4b, 0x65, 0x79, 0x6d, 0x61, 0x70, 0x45, 0x6e,
-	0x74, 0x72, 0x79, 0x52, 0x06, 0x6b, 0x65, 0x79, 0x4d, 0x61, 0x70, 0x12, 0x33, 0x0a, 0x0b, 0x6d,
-	0x6f, 0x64, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x6d, 0x61, 0x70, 0x18, 0x15, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x13, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4b, 0x65, 0x79, 0x6d, 0x61, 0x70,
-	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x6d, 0x6f, 0x64, 0x4b, 0x65, 0x79, 0x4d, 0x61, 0x70,
-	0x12, 0x42, 0x0a, 0x13, 0x74, 0x68, 0x69, 0x72, 0x64, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x5f,
-	0x6b, 0x65, 0x79, 0x5f, 0x6d, 0x61, 0x70, 0x18, 0x16, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e,
-	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4b, 0x65, 0x79, 0x6d, 0x61, 0x70, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x52, 0x10, 0x74, 0x68, 0x69, 0x72, 0x64, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x4b, 0x65,
-	0x79, 0x4d, 0x61, 0x70, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x75, 0x73, 0x65, 0x5f, 0x6c, 0x65, 0x64,
-	0x42, 0x2c, 0x5a, 0x2a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65,
-	0x72, 0x64, 0x69, 0x63, 0x68, 0x65, 0x6e, 0x2f, 0x63, 0x68, 0x72, 0x6f, 0x6d, 0x65, 0x6b, 0x65,
-	0x79, 0x2f, 0x72, 0x65, 0x6d, 0x61, 0x70, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x02, 0x74, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x6d,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x4d, 0x73, 0x12, 0x1f, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x13, 0x20, 0x03, 0x28, 0x0e, 0x32,
+	0x0d, 0x2e, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x22, 0xb5, 0x01, 0x0a, 0x0a, 0x4c, 0x45, 0x44, 0x42, 0x69, 0x6e, 0x64, 0x69,
+	0x6e, 0x67, 0x12, 0x1e, 0x0a, 0x03, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x0c, 0x2e, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x4c, 0x45, 0x44, 0x52, 0x03, 0x6c,
+	0x65, 0x64, 0x12, 0x26, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x10, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4c, 0x45, 0x44, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x12, 0x2c, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x12, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4c, 0x45, 0x44, 0x50, 0x61,
+	0x74, 0x74, 0x65, 0x72, 0x6e, 0x52, 0x07, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x12, 0x1b,
+	0x0a, 0x09, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x5f, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x08, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x4d, 0x73, 0x22, 0xf6, 0x05, 0x0a, 0x0c,
+	0x4b, 0x65, 0x79, 0x6d, 0x61, 0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1d, 0x0a, 0x0a,
+	0x66, 0x6e, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x09, 0x66, 0x6e, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x24, 0x0a, 0x06, 0x66,
+	0x6e, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x6b, 0x65,
+	0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x66, 0x6e, 0x4b, 0x65,
+	0x79, 0x12, 0x2a, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x5f, 0x6c, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x4c, 0x45, 0x44,
+	0x48, 0x00, 0x52, 0x06, 0x75, 0x73, 0x65, 0x4c, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x33, 0x0a,
+	0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x12, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x0a, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x4d, 0x6f,
+	0x64, 0x65, 0x12, 0x3c, 0x0a, 0x0e, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x64, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x2e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x52, 0x0d, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x35, 0x0a, 0x0f, 0x74, 0x68, 0x69, 0x72, 0x64, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x13, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x6b, 0x65, 0x79, 0x63,
+	0x6f, 0x64, 0x65, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x0d, 0x74, 0x68, 0x69, 0x72, 0x64, 0x4c,
+	0x65, 0x76, 0x65, 0x6c, 0x4b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x07, 0x6b, 0x65, 0x79, 0x5f, 0x6d,
+	0x61, 0x70, 0x18, 0x14, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x2e, 0x4b, 0x65, 0x79, 0x6d, 0x61, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6b,
+	0x65, 0x79, 0x4d, 0x61, 0x70, 0x12, 0x33, 0x0a, 0x0b, 0x6d, 0x6f, 0x64, 0x5f, 0x6b, 0x65, 0x79,
+	0x5f, 0x6d, 0x61, 0x70, 0x18, 0x15, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x2e, 0x4b, 0x65, 0x79, 0x6d, 0x61, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x09, 0x6d, 0x6f, 0x64, 0x4b, 0x65, 0x79, 0x4d, 0x61, 0x70, 0x12, 0x42, 0x0a, 0x13, 0x74, 0x68,
+	0x69, 0x72, 0x64, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x6d, 0x61,
+	0x70, 0x18, 0x16, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x2e, 0x4b, 0x65, 0x79, 0x6d, 0x61, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x10, 0x74, 0x68,
+	0x69, 0x72, 0x64, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x4b, 0x65, 0x79, 0x4d, 0x61, 0x70, 0x12, 0x23,
+	0x0a, 0x05, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x17, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x05, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x12, 0x2a, 0x0a, 0x08, 0x74, 0x61, 0x70, 0x5f, 0x68, 0x6f, 0x6c, 0x64, 0x18,
+	0x18, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x54,
+	0x61, 0x70, 0x48, 0x6f, 0x6c, 0x64, 0x52, 0x07, 0x74, 0x61, 0x70, 0x48, 0x6f, 0x6c, 0x64, 0x12,
+	0x23, 0x0a, 0x05, 0x63, 0x6f, 0x6d, 0x62, 0x6f, 0x18, 0x19, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x62, 0x6f, 0x52, 0x05, 0x63,
+	0x6f, 0x6d, 0x62, 0x6f, 0x12, 0x23, 0x0a, 0x0d, 0x6b, 0x65, 0x79, 0x62, 0x6f, 0x61, 0x72, 0x64,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x1a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x6b, 0x65, 0x79,
+	0x62, 0x6f, 0x61, 0x72, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x26, 0x0a, 0x06, 0x64, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x18, 0x1b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x06, 0x64, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x2f, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x18, 0x1c, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x44, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x62, 0x6f, 0x61,
+	0x72, 0x64, 0x12, 0x24, 0x0a, 0x03, 0x6c, 0x65, 0x64, 0x18, 0x1d, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4c, 0x45, 0x44, 0x42, 0x69, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x52, 0x03, 0x6c, 0x65, 0x64, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x75, 0x73, 0x65,
+	0x5f, 0x6c, 0x65, 0x64, 0x22, 0xcf, 0x01, 0x0a, 0x0b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4d,
+	0x61, 0x74, 0x63, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x61, 0x6d, 0x65,
+	0x5f, 0x72, 0x65, 0x67, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61,
+	0x6d, 0x65, 0x52, 0x65, 0x67, 0x65, 0x78, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x03, 0x62, 0x75, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x42,
+	0x75, 0x73, 0x52, 0x03, 0x62, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x68, 0x79, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x68, 0x79, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6c,
+	0x69, 0x6e, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x6b, 0x12,
+	0x1b, 0x0a, 0x09, 0x6d, 0x69, 0x6e, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x08, 0x6d, 0x69, 0x6e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x76,
+	0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x22, 0xba, 0x01, 0x0a, 0x0d, 0x56, 0x69, 0x72, 0x74, 0x75,
+	0x61, 0x6c, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x03,
+	0x62, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x6b, 0x65, 0x79, 0x63,
+	0x6f, 0x64, 0x65, 0x2e, 0x42, 0x75, 0x73, 0x52, 0x03, 0x62, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x68, 0x79, 0x73, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x68, 0x79, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f,
+	0x70, 0x79, 0x5f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0a, 0x63, 0x6f, 0x70, 0x79, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73,
+	0x75, 0x66, 0x66, 0x69, 0x78, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x75, 0x66,
+	0x66, 0x69, 0x78, 0x22, 0xf7, 0x01, 0x0a, 0x06, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72,
+	0x6f, 0x64, 0x75, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x70, 0x72, 0x6f,
+	0x64, 0x75, 0x63, 0x74, 0x12, 0x1e, 0x0a, 0x03, 0x62, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x0c, 0x2e, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x42, 0x75, 0x73, 0x52,
+	0x03, 0x62, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x68, 0x79, 0x73, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x70, 0x68, 0x79, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x61, 0x6c, 0x6c,
+	0x62, 0x61, 0x63, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x66, 0x61, 0x6c, 0x6c,
+	0x62, 0x61, 0x63, 0x6b, 0x12, 0x2c, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4b, 0x65,
+	0x79, 0x6d, 0x61, 0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x12, 0x29, 0x0a, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x13, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2a, 0x39, 0x0a,
+	0x0b, 0x4d, 0x61, 0x63, 0x72, 0x6f, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x07, 0x0a, 0x03,
+	0x54, 0x41, 0x50, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x50, 0x52, 0x45, 0x53, 0x53, 0x10, 0x01,
+	0x12, 0x0b, 0x0a, 0x07, 0x52, 0x45, 0x4c, 0x45, 0x41, 0x53, 0x45, 0x10, 0x02, 0x12, 0x09, 0x0a,
+	0x05, 0x44, 0x45, 0x4c, 0x41, 0x59, 0x10, 0x03, 0x2a, 0x34, 0x0a, 0x09, 0x4c, 0x61, 0x79, 0x65,
+	0x72, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x0d, 0x0a, 0x09, 0x4d, 0x4f, 0x4d, 0x45, 0x4e, 0x54, 0x41,
+	0x52, 0x59, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x54, 0x4f, 0x47, 0x47, 0x4c, 0x45, 0x10, 0x01,
+	0x12, 0x0c, 0x0a, 0x08, 0x4f, 0x4e, 0x45, 0x5f, 0x53, 0x48, 0x4f, 0x54, 0x10, 0x02, 0x2a, 0x4e,
+	0x0a, 0x08, 0x4c, 0x45, 0x44, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x4e,
+	0x5f, 0x4c, 0x4f, 0x43, 0x4b, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x4c, 0x41, 0x59, 0x45, 0x52,
+	0x5f, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x4d, 0x41, 0x43,
+	0x52, 0x4f, 0x5f, 0x50, 0x4c, 0x41, 0x59, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c,
+	0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x03, 0x2a, 0x23,
+	0x0a, 0x0a, 0x4c, 0x45, 0x44, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x12, 0x0a, 0x0a, 0x06,
+	0x53, 0x54, 0x45, 0x41, 0x44, 0x59, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x42, 0x4c, 0x49, 0x4e,
+	0x4b, 0x10, 0x01, 0x2a, 0x36, 0x0a, 0x0a, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x4d, 0x6f, 0x64,
+	0x65, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x48, 0x41, 0x52, 0x45, 0x44, 0x5f, 0x4f, 0x55, 0x54, 0x50,
+	0x55, 0x54, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x45, 0x52, 0x5f, 0x44, 0x45, 0x56, 0x49,
+	0x43, 0x45, 0x5f, 0x4f, 0x55, 0x54, 0x50, 0x55, 0x54, 0x10, 0x01, 0x42, 0x2c, 0x5a, 0x2a, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x72, 0x64, 0x69, 0x63, 0x68,
+	0x65, 0x6e, 0x2f, 0x63, 0x68, 0x72, 0x6f, 0x6d, 0x65, 0x6b, 0x65, 0x79, 0x2f, 0x72, 0x65, 0x6d,
+	0x61, 0x70, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -224,27 +1478,74 @@ func file_config_proto_rawDescGZIP() []byte {
 	return file_config_proto_rawDescData
 }
 
-var file_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_config_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_config_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_config_proto_goTypes = []interface{}{
-	(*KeymapEntry)(nil),  // 0: config.KeymapEntry
-	(*KeymapConfig)(nil), // 1: config.KeymapConfig
-	(keycode.Code)(0),    // 2: keycode.Code
-	(keycode.LED)(0),     // 3: keycode.LED
+	(MacroAction)(0),      // 0: config.MacroAction
+	(LayerMode)(0),        // 1: config.LayerMode
+	(LEDState)(0),         // 2: config.LEDState
+	(LEDPattern)(0),       // 3: config.LEDPattern
+	(OutputMode)(0),       // 4: config.OutputMode
+	(*MacroStep)(nil),     // 5: config.MacroStep
+	(*KeymapEntry)(nil),   // 6: config.KeymapEntry
+	(*Layer)(nil),         // 7: config.Layer
+	(*TapHold)(nil),       // 8: config.TapHold
+	(*Combo)(nil),         // 9: config.Combo
+	(*LEDBinding)(nil),    // 10: config.LEDBinding
+	(*KeymapConfig)(nil),  // 11: config.KeymapConfig
+	(*DeviceMatch)(nil),   // 12: config.DeviceMatch
+	(*VirtualDevice)(nil), // 13: config.VirtualDevice
+	(*Device)(nil),        // 14: config.Device
+	(keycode.Code)(0),     // 15: keycode.Code
+	(keycode.LED)(0),      // 16: keycode.LED
+	(keycode.Bus)(0),      // 17: keycode.Bus
 }
 var file_config_proto_depIdxs = []int32{
-	2, // 0: config.KeymapEntry.from:type_name -> keycode.Code
-	2, // 1: config.KeymapEntry.to:type_name -> keycode.Code
-	2, // 2: config.KeymapConfig.fn_key:type_name -> keycode.Code
-	3, // 3: config.KeymapConfig.use_led:type_name -> keycode.LED
-	2, // 4: config.KeymapConfig.third_level_key:type_name -> keycode.Code
-	0, // 5: config.KeymapConfig.key_map:type_name -> config.KeymapEntry
-	0, // 6: config.KeymapConfig.mod_key_map:type_name -> config.KeymapEntry
-	0, // 7: config.KeymapConfig.third_level_key_map:type_name -> config.KeymapEntry
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	8, // [8:8] is the sub-list for extension extendee
-	0, // [0:8] is the sub-list for field type_name
+	0,  // 0: config.MacroStep.action:type_name -> config.MacroAction
+	15, // 1: config.MacroStep.key:type_name -> keycode.Code
+	15, // 2: config.KeymapEntry.from:type_name -> keycode.Code
+	15, // 3: config.KeymapEntry.to:type_name -> keycode.Code
+	5,  // 4: config.KeymapEntry.macro:type_name -> config.MacroStep
+	15, // 5: config.KeymapEntry.add_mod:type_name -> keycode.Code
+	15, // 6: config.KeymapEntry.suppress_mod:type_name -> keycode.Code
+	15, // 7: config.KeymapEntry.require_mod:type_name -> keycode.Code
+	15, // 8: config.KeymapEntry.forbid_mod:type_name -> keycode.Code
+	1,  // 9: config.Layer.mode:type_name -> config.LayerMode
+	15, // 10: config.Layer.send_key:type_name -> keycode.Code
+	15, // 11: config.Layer.key:type_name -> keycode.Code
+	6,  // 12: config.Layer.key_map:type_name -> config.KeymapEntry
+	15, // 13: config.TapHold.key:type_name -> keycode.Code
+	15, // 14: config.TapHold.tap:type_name -> keycode.Code
+	15, // 15: config.TapHold.hold:type_name -> keycode.Code
+	15, // 16: config.Combo.to:type_name -> keycode.Code
+	15, // 17: config.Combo.key:type_name -> keycode.Code
+	16, // 18: config.LEDBinding.led:type_name -> keycode.LED
+	2,  // 19: config.LEDBinding.state:type_name -> config.LEDState
+	3,  // 20: config.LEDBinding.pattern:type_name -> config.LEDPattern
+	15, // 21: config.KeymapConfig.fn_key:type_name -> keycode.Code
+	16, // 22: config.KeymapConfig.use_led:type_name -> keycode.LED
+	4,  // 23: config.KeymapConfig.output_mode:type_name -> config.OutputMode
+	13, // 24: config.KeymapConfig.virtual_device:type_name -> config.VirtualDevice
+	15, // 25: config.KeymapConfig.third_level_key:type_name -> keycode.Code
+	6,  // 26: config.KeymapConfig.key_map:type_name -> config.KeymapEntry
+	6,  // 27: config.KeymapConfig.mod_key_map:type_name -> config.KeymapEntry
+	6,  // 28: config.KeymapConfig.third_level_key_map:type_name -> config.KeymapEntry
+	7,  // 29: config.KeymapConfig.layer:type_name -> config.Layer
+	8,  // 30: config.KeymapConfig.tap_hold:type_name -> config.TapHold
+	9,  // 31: config.KeymapConfig.combo:type_name -> config.Combo
+	14, // 32: config.KeymapConfig.device:type_name -> config.Device
+	12, // 33: config.KeymapConfig.keyboard:type_name -> config.DeviceMatch
+	10, // 34: config.KeymapConfig.led:type_name -> config.LEDBinding
+	17, // 35: config.DeviceMatch.bus:type_name -> keycode.Bus
+	17, // 36: config.VirtualDevice.bus:type_name -> keycode.Bus
+	17, // 37: config.Device.bus:type_name -> keycode.Bus
+	11, // 38: config.Device.config:type_name -> config.KeymapConfig
+	12, // 39: config.Device.match:type_name -> config.DeviceMatch
+	40, // [40:40] is the sub-list for method output_type
+	40, // [40:40] is the sub-list for method input_type
+	40, // [40:40] is the sub-list for extension type_name
+	40, // [40:40] is the sub-list for extension extendee
+	0,  // [0:40] is the sub-list for field type_name
 }
 
 func init() { file_config_proto_init() }
@@ -254,7 +1555,7 @@ func file_config_proto_init() {
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_config_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KeymapEntry); i {
+			switch v := v.(*MacroStep); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -266,6 +1567,66 @@ func file_config_proto_init() {
 			}
 		}
 		file_config_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeymapEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_config_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Layer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_config_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TapHold); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_config_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Combo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_config_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LEDBinding); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_config_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*KeymapConfig); i {
 			case 0:
 				return &v.state
@@ -277,20 +1638,57 @@ func file_config_proto_init() {
 				return nil
 			}
 		}
+		file_config_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeviceMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_config_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VirtualDevice); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_config_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Device); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
-	file_config_proto_msgTypes[1].OneofWrappers = []interface{}{}
+	file_config_proto_msgTypes[6].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_config_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      5,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_config_proto_goTypes,
 		DependencyIndexes: file_config_proto_depIdxs,
+		EnumInfos:         file_config_proto_enumTypes,
 		MessageInfos:      file_config_proto_msgTypes,
 	}.Build()
 	File_config_proto = out.File
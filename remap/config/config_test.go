@@ -0,0 +1,103 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/erdichen/chromekey/evdev"
+	keycode "github.com/erdichen/chromekey/evdev/keycode"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+func TestFromPBDevice(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want DeviceMatchConfig
+	}{
+		{
+			name: "shorthand",
+			text: `name: "ThinkPad" vendor: 0x17ef product: 0x6047 bus: BUS_USB phys: "usb" fallback: true`,
+			want: DeviceMatchConfig{Name: "ThinkPad", ID: "17ef:6047", Bus: keycode.Bus_BUS_USB, Phys: "usb"},
+		},
+		{
+			name: "match",
+			text: `name: "ThinkPad" vendor: 0x17ef product: 0x6047 match: { name_regex: "^AT" id: "0001:0001" }`,
+			want: DeviceMatchConfig{Name: "ThinkPad", NameRegex: "^AT", ID: "0001:0001"},
+		},
+	}
+	for _, tc := range tests {
+		pb := &Device{}
+		if err := prototext.Unmarshal([]byte(tc.text), pb); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got := FromPBDevice(pb).Match; got != tc.want {
+			t.Errorf("%s: got %+v want %+v", tc.name, got, tc.want)
+		}
+	}
+
+	d := FromPBDevice(&Device{Name: "ThinkPad"})
+	if d.Config.FnKey != keycode.Code_KEY_F13 || d.Config.UseLED != keycode.LED_CNT || len(d.Config.KeyMap) == 0 {
+		t.Errorf("no config: got FN key %v LED %v with %d keys, want the default key maps", d.Config.FnKey, d.Config.UseLED, len(d.Config.KeyMap))
+	}
+}
+
+func TestVirtualDeviceIdentity(t *testing.T) {
+	src := &evdev.Info{Name: "AT Translated Set 2 keyboard", Phys: "isa0060/serio0/input0", ID: evdev.InputID{BusType: 0x11, Vendor: 1, Product: 1, Version: 0xab83}}
+	tests := []struct {
+		name     string
+		v        VirtualDeviceConfig
+		wantName string
+		wantPhys string
+		wantID   evdev.InputID
+		wantErr  bool
+	}{
+		{
+			name:     "default",
+			wantName: evdev.RemapDeviceName,
+			wantID:   evdev.InputID{BusType: uint16(keycode.Bus_BUS_USB), Vendor: 1, Product: 1, Version: 9999},
+		},
+		{
+			name:     "copy source",
+			v:        VirtualDeviceConfig{CopySource: true},
+			wantName: src.Name,
+			wantPhys: src.Phys,
+			wantID:   src.ID,
+		},
+		{
+			name:     "suffix",
+			v:        VirtualDeviceConfig{CopySource: true, Suffix: " (remap)"},
+			wantName: src.Name + " (remap)",
+			wantPhys: src.Phys,
+			wantID:   src.ID,
+		},
+		{
+			name:     "override",
+			v:        VirtualDeviceConfig{CopySource: true, Suffix: " (remap)", Name: "Remap", ID: "17ef:6047", Bus: keycode.Bus_BUS_BLUETOOTH, Version: 2, Phys: "remap"},
+			wantName: "Remap",
+			wantPhys: "remap",
+			wantID:   evdev.InputID{BusType: uint16(keycode.Bus_BUS_BLUETOOTH), Vendor: 0x17ef, Product: 0x6047, Version: 2},
+		},
+		{
+			name:    "invalid id",
+			v:       VirtualDeviceConfig{ID: "17ef"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid version",
+			v:       VirtualDeviceConfig{Version: 0x10000},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		name, phys, id, err := tc.v.Identity(src)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%s: got no error", tc.name)
+			}
+			continue
+		}
+		if err != nil || name != tc.wantName || phys != tc.wantPhys || id != tc.wantID {
+			t.Errorf("%s: got %q %q %+v %v want %q %q %+v", tc.name, name, phys, id, err, tc.wantName, tc.wantPhys, tc.wantID)
+		}
+	}
+}
@@ -0,0 +1,731 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.12.4
+// source: control.proto
+
+package config
+
+import (
+	keycode "github.com/erdichen/chromekey/evdev/keycode"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// DeviceStatus is the state of an input device attached to the remapper.
+type DeviceStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path   string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Name   string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Id     string `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`                        // Vendor and product IDs in hexadecimal "vendor:product" format
+	FnLock bool   `protobuf:"varint,4,opt,name=fn_lock,json=fnLock,proto3" json:"fn_lock,omitempty"` // FN lock state of the key maps of the device
+	// Reserved tags here for future non-repeating fields.
+	ActiveLayer []string `protobuf:"bytes,19,rep,name=active_layer,json=activeLayer,proto3" json:"active_layer,omitempty"` // Active layers of the key maps of the device
+}
+
+func (x *DeviceStatus) Reset() {
+	*x = DeviceStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_control_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeviceStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceStatus) ProtoMessage() {}
+
+func (x *DeviceStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceStatus.ProtoReflect.Descriptor instead.
+func (*DeviceStatus) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DeviceStatus) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DeviceStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DeviceStatus) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeviceStatus) GetFnLock() bool {
+	if x != nil {
+		return x.FnLock
+	}
+	return false
+}
+
+func (x *DeviceStatus) GetActiveLayer() []string {
+	if x != nil {
+		return x.ActiveLayer
+	}
+	return nil
+}
+
+// Status is the state of the remapper.
+type Status struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FnLock      bool `protobuf:"varint,1,opt,name=fn_lock,json=fnLock,proto3" json:"fn_lock,omitempty"`                // FN lock state of the top-level key maps
+	Paused      bool `protobuf:"varint,2,opt,name=paused,proto3" json:"paused,omitempty"`                              // Keys are forwarded without remapping
+	ConfigError bool `protobuf:"varint,3,opt,name=config_error,json=configError,proto3" json:"config_error,omitempty"` // The last configuration reload failed
+	// Reserved tags here for future non-repeating fields.
+	PressedKey  []keycode.Code  `protobuf:"varint,19,rep,packed,name=pressed_key,json=pressedKey,proto3,enum=keycode.Code" json:"pressed_key,omitempty"`
+	ActiveLayer []string        `protobuf:"bytes,20,rep,name=active_layer,json=activeLayer,proto3" json:"active_layer,omitempty"` // Active layers of the top-level key maps
+	Device      []*DeviceStatus `protobuf:"bytes,21,rep,name=device,proto3" json:"device,omitempty"`
+}
+
+func (x *Status) Reset() {
+	*x = Status{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_control_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Status) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Status) ProtoMessage() {}
+
+func (x *Status) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Status.ProtoReflect.Descriptor instead.
+func (*Status) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Status) GetFnLock() bool {
+	if x != nil {
+		return x.FnLock
+	}
+	return false
+}
+
+func (x *Status) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *Status) GetConfigError() bool {
+	if x != nil {
+		return x.ConfigError
+	}
+	return false
+}
+
+func (x *Status) GetPressedKey() []keycode.Code {
+	if x != nil {
+		return x.PressedKey
+	}
+	return nil
+}
+
+func (x *Status) GetActiveLayer() []string {
+	if x != nil {
+		return x.ActiveLayer
+	}
+	return nil
+}
+
+func (x *Status) GetDevice() []*DeviceStatus {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+// SetFnLock sets the FN lock state of all the key maps.
+type SetFnLock struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	On bool `protobuf:"varint,1,opt,name=on,proto3" json:"on,omitempty"`
+}
+
+func (x *SetFnLock) Reset() {
+	*x = SetFnLock{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_control_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetFnLock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetFnLock) ProtoMessage() {}
+
+func (x *SetFnLock) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetFnLock.ProtoReflect.Descriptor instead.
+func (*SetFnLock) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SetFnLock) GetOn() bool {
+	if x != nil {
+		return x.On
+	}
+	return false
+}
+
+// Empty is a request without parameters.
+type Empty struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *Empty) Reset() {
+	*x = Empty{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_control_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Empty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Empty) ProtoMessage() {}
+
+func (x *Empty) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
+func (*Empty) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{3}
+}
+
+// ControlRequest is a request sent to the control socket.
+type ControlRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Op:
+	//	*ControlRequest_GetStatus
+	//	*ControlRequest_SetFnLock
+	//	*ControlRequest_GetConfig
+	//	*ControlRequest_SetConfig
+	//	*ControlRequest_Reload
+	//	*ControlRequest_Pause
+	//	*ControlRequest_Resume
+	//	*ControlRequest_Subscribe
+	Op isControlRequest_Op `protobuf_oneof:"op"`
+}
+
+func (x *ControlRequest) Reset() {
+	*x = ControlRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_control_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ControlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ControlRequest) ProtoMessage() {}
+
+func (x *ControlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ControlRequest.ProtoReflect.Descriptor instead.
+func (*ControlRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{4}
+}
+
+func (m *ControlRequest) GetOp() isControlRequest_Op {
+	if m != nil {
+		return m.Op
+	}
+	return nil
+}
+
+func (x *ControlRequest) GetGetStatus() *Empty {
+	if x, ok := x.GetOp().(*ControlRequest_GetStatus); ok {
+		return x.GetStatus
+	}
+	return nil
+}
+
+func (x *ControlRequest) GetSetFnLock() *SetFnLock {
+	if x, ok := x.GetOp().(*ControlRequest_SetFnLock); ok {
+		return x.SetFnLock
+	}
+	return nil
+}
+
+func (x *ControlRequest) GetGetConfig() *Empty {
+	if x, ok := x.GetOp().(*ControlRequest_GetConfig); ok {
+		return x.GetConfig
+	}
+	return nil
+}
+
+func (x *ControlRequest) GetSetConfig() *KeymapConfig {
+	if x, ok := x.GetOp().(*ControlRequest_SetConfig); ok {
+		return x.SetConfig
+	}
+	return nil
+}
+
+func (x *ControlRequest) GetReload() *Empty {
+	if x, ok := x.GetOp().(*ControlRequest_Reload); ok {
+		return x.Reload
+	}
+	return nil
+}
+
+func (x *ControlRequest) GetPause() *Empty {
+	if x, ok := x.GetOp().(*ControlRequest_Pause); ok {
+		return x.Pause
+	}
+	return nil
+}
+
+func (x *ControlRequest) GetResume() *Empty {
+	if x, ok := x.GetOp().(*ControlRequest_Resume); ok {
+		return x.Resume
+	}
+	return nil
+}
+
+func (x *ControlRequest) GetSubscribe() *Empty {
+	if x, ok := x.GetOp().(*ControlRequest_Subscribe); ok {
+		return x.Subscribe
+	}
+	return nil
+}
+
+type isControlRequest_Op interface {
+	isControlRequest_Op()
+}
+
+type ControlRequest_GetStatus struct {
+	GetStatus *Empty `protobuf:"bytes,1,opt,name=get_status,json=getStatus,proto3,oneof"`
+}
+
+type ControlRequest_SetFnLock struct {
+	SetFnLock *SetFnLock `protobuf:"bytes,2,opt,name=set_fn_lock,json=setFnLock,proto3,oneof"`
+}
+
+type ControlRequest_GetConfig struct {
+	GetConfig *Empty `protobuf:"bytes,3,opt,name=get_config,json=getConfig,proto3,oneof"`
+}
+
+type ControlRequest_SetConfig struct {
+	SetConfig *KeymapConfig `protobuf:"bytes,4,opt,name=set_config,json=setConfig,proto3,oneof"` // Replaces the running configuration until the next reload
+}
+
+type ControlRequest_Reload struct {
+	Reload *Empty `protobuf:"bytes,5,opt,name=reload,proto3,oneof"` // Loads the configuration file again
+}
+
+type ControlRequest_Pause struct {
+	Pause *Empty `protobuf:"bytes,6,opt,name=pause,proto3,oneof"` // Forwards keys without remapping
+}
+
+type ControlRequest_Resume struct {
+	Resume *Empty `protobuf:"bytes,7,opt,name=resume,proto3,oneof"`
+}
+
+type ControlRequest_Subscribe struct {
+	Subscribe *Empty `protobuf:"bytes,8,opt,name=subscribe,proto3,oneof"` // Replies and then streams the state changes as JSON lines until the connection is closed
+}
+
+func (*ControlRequest_GetStatus) isControlRequest_Op() {}
+
+func (*ControlRequest_SetFnLock) isControlRequest_Op() {}
+
+func (*ControlRequest_GetConfig) isControlRequest_Op() {}
+
+func (*ControlRequest_SetConfig) isControlRequest_Op() {}
+
+func (*ControlRequest_Reload) isControlRequest_Op() {}
+
+func (*ControlRequest_Pause) isControlRequest_Op() {}
+
+func (*ControlRequest_Resume) isControlRequest_Op() {}
+
+func (*ControlRequest_Subscribe) isControlRequest_Op() {}
+
+// ControlResponse is the reply to a ControlRequest.
+type ControlResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Error  string        `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`   // Empty if the request succeeded
+	Status *Status       `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // Reply to get_status
+	Config *KeymapConfig `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"` // Reply to get_config
+}
+
+func (x *ControlResponse) Reset() {
+	*x = ControlResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_control_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ControlResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ControlResponse) ProtoMessage() {}
+
+func (x *ControlResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ControlResponse.ProtoReflect.Descriptor instead.
+func (*ControlResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ControlResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ControlResponse) GetStatus() *Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *ControlResponse) GetConfig() *KeymapConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+var File_control_proto protoreflect.FileDescriptor
+
+var file_control_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x1a, 0x1b, 0x65, 0x76, 0x64, 0x65, 0x76, 0x2f, 0x6b,
+	0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2f, 0x6b, 0x65, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0c, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0x82, 0x01, 0x0a, 0x0c, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x66,
+	0x6e, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x6e,
+	0x4c, 0x6f, 0x63, 0x6b, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x18, 0x13, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x63, 0x74, 0x69,
+	0x76, 0x65, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x22, 0xdd, 0x01, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x66, 0x6e, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x6e, 0x4c, 0x6f, 0x63, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x70,
+	0x61, 0x75, 0x73, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70, 0x61, 0x75,
+	0x73, 0x65, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2e, 0x0a, 0x0b, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65,
+	0x64, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x13, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x6b, 0x65,
+	0x79, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x0a, 0x70, 0x72, 0x65, 0x73,
+	0x73, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65,
+	0x5f, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x14, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x2c, 0x0a, 0x06, 0x64, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x18, 0x15, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x06, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x22, 0x1b, 0x0a, 0x09, 0x53, 0x65, 0x74, 0x46, 0x6e,
+	0x4c, 0x6f, 0x63, 0x6b, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x02, 0x6f, 0x6e, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x8a, 0x03,
+	0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x2e, 0x0a, 0x0a, 0x67, 0x65, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x48, 0x00, 0x52, 0x09, 0x67, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x33, 0x0a, 0x0b, 0x73, 0x65, 0x74, 0x5f, 0x66, 0x6e, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x53,
+	0x65, 0x74, 0x46, 0x6e, 0x4c, 0x6f, 0x63, 0x6b, 0x48, 0x00, 0x52, 0x09, 0x73, 0x65, 0x74, 0x46,
+	0x6e, 0x4c, 0x6f, 0x63, 0x6b, 0x12, 0x2e, 0x0a, 0x0a, 0x67, 0x65, 0x74, 0x5f, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00, 0x52, 0x09, 0x67, 0x65, 0x74, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x35, 0x0a, 0x0a, 0x73, 0x65, 0x74, 0x5f, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x2e, 0x4b, 0x65, 0x79, 0x6d, 0x61, 0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48,
+	0x00, 0x52, 0x09, 0x73, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x27, 0x0a, 0x06,
+	0x72, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00, 0x52, 0x06, 0x72,
+	0x65, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x25, 0x0a, 0x05, 0x70, 0x61, 0x75, 0x73, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x48, 0x00, 0x52, 0x05, 0x70, 0x61, 0x75, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00, 0x52, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x09, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00, 0x52, 0x09, 0x73, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x42, 0x04, 0x0a, 0x02, 0x6f, 0x70, 0x22, 0x7d, 0x0a, 0x0f, 0x43, 0x6f,
+	0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x12, 0x26, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2c, 0x0a, 0x06, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4b, 0x65, 0x79, 0x6d, 0x61, 0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x42, 0x2c, 0x5a, 0x2a, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x72, 0x64, 0x69, 0x63, 0x68, 0x65, 0x6e,
+	0x2f, 0x63, 0x68, 0x72, 0x6f, 0x6d, 0x65, 0x6b, 0x65, 0x79, 0x2f, 0x72, 0x65, 0x6d, 0x61, 0x70,
+	0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_control_proto_rawDescOnce sync.Once
+	file_control_proto_rawDescData = file_control_proto_rawDesc
+)
+
+func file_control_proto_rawDescGZIP() []byte {
+	file_control_proto_rawDescOnce.Do(func() {
+		file_control_proto_rawDescData = protoimpl.X.CompressGZIP(file_control_proto_rawDescData)
+	})
+	return file_control_proto_rawDescData
+}
+
+var file_control_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_control_proto_goTypes = []interface{}{
+	(*DeviceStatus)(nil),    // 0: config.DeviceStatus
+	(*Status)(nil),          // 1: config.Status
+	(*SetFnLock)(nil),       // 2: config.SetFnLock
+	(*Empty)(nil),           // 3: config.Empty
+	(*ControlRequest)(nil),  // 4: config.ControlRequest
+	(*ControlResponse)(nil), // 5: config.ControlResponse
+	(keycode.Code)(0),       // 6: keycode.Code
+	(*KeymapConfig)(nil),    // 7: config.KeymapConfig
+}
+var file_control_proto_depIdxs = []int32{
+	6,  // 0: config.Status.pressed_key:type_name -> keycode.Code
+	0,  // 1: config.Status.device:type_name -> config.DeviceStatus
+	3,  // 2: config.ControlRequest.get_status:type_name -> config.Empty
+	2,  // 3: config.ControlRequest.set_fn_lock:type_name -> config.SetFnLock
+	3,  // 4: config.ControlRequest.get_config:type_name -> config.Empty
+	7,  // 5: config.ControlRequest.set_config:type_name -> config.KeymapConfig
+	3,  // 6: config.ControlRequest.reload:type_name -> config.Empty
+	3,  // 7: config.ControlRequest.pause:type_name -> config.Empty
+	3,  // 8: config.ControlRequest.resume:type_name -> config.Empty
+	3,  // 9: config.ControlRequest.subscribe:type_name -> config.Empty
+	1,  // 10: config.ControlResponse.status:type_name -> config.Status
+	7,  // 11: config.ControlResponse.config:type_name -> config.KeymapConfig
+	12, // [12:12] is the sub-list for method output_type
+	12, // [12:12] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_control_proto_init() }
+func file_control_proto_init() {
+	if File_control_proto != nil {
+		return
+	}
+	file_config_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_control_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeviceStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_control_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Status); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_control_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetFnLock); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_control_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Empty); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_control_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ControlRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_control_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ControlResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_control_proto_msgTypes[4].OneofWrappers = []interface{}{
+		(*ControlRequest_GetStatus)(nil),
+		(*ControlRequest_SetFnLock)(nil),
+		(*ControlRequest_GetConfig)(nil),
+		(*ControlRequest_SetConfig)(nil),
+		(*ControlRequest_Reload)(nil),
+		(*ControlRequest_Pause)(nil),
+		(*ControlRequest_Resume)(nil),
+		(*ControlRequest_Subscribe)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_control_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_control_proto_goTypes,
+		DependencyIndexes: file_control_proto_depIdxs,
+		MessageInfos:      file_control_proto_msgTypes,
+	}.Build()
+	File_control_proto = out.File
+	file_control_proto_rawDesc = nil
+	file_control_proto_goTypes = nil
+	file_control_proto_depIdxs = nil
+}
@@ -1,3 +1,3 @@
 package config
 
-//go:generate protoc -I.:../.. --go_out=module=github.com/erdichen/chromekey:../.. --experimental_allow_proto3_optional config.proto
+//go:generate protoc -I.:../.. --go_out=module=github.com/erdichen/chromekey:../.. --experimental_allow_proto3_optional config.proto control.proto
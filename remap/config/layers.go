@@ -0,0 +1,62 @@
+package config
+
+import "github.com/erdichen/chromekey/evdev/keycode"
+
+// Names of the default layers that emulate the FN key.
+const (
+	FnLockLayer     = "fn_lock"
+	FnLayer         = "fn"
+	ShiftLayer      = "shift"
+	ThirdLevelLayer = "third_level"
+)
+
+// EffectiveLayers returns the configured layers, or the default FN key layers if none is configured.
+func (cfg RunConfig) EffectiveLayers() []LayerConfig {
+	if len(cfg.Layers) > 0 {
+		return cfg.Clone().Layers
+	}
+	return cfg.FnLayers()
+}
+
+// FnLayers returns the layer set built from the FN key settings of a RunConfig, in increasing priority order.
+//
+//  1. fn_lock: The key map toggled by tapping the FN key.
+//  2. fn: The FN key modifier map. Holding FN inverts the FN lock state.
+//  3. shift: Disables the FN key maps while a third level key is held.
+//  4. third_level: The FN+third level key map.
+func (cfg RunConfig) FnLayers() []LayerConfig {
+	return []LayerConfig{
+		{
+			Name:        FnLockLayer,
+			Mode:        LayerMode_TOGGLE,
+			Enabled:     cfg.FnEnabled,
+			Transparent: true,
+			SendKey:     keycode.Code_KEY_FN,
+			Keys:        []keycode.Code{cfg.FnKey},
+			KeyMap:      cfg.KeyMap.Clone(),
+		},
+		{
+			Name:        FnLayer,
+			Mode:        LayerMode_MOMENTARY,
+			Transparent: true,
+			InvertLayer: FnLockLayer,
+			SendKey:     keycode.Code_KEY_FN,
+			Keys:        []keycode.Code{cfg.FnKey},
+			KeyMap:      cfg.ModKeyMap.Clone(),
+		},
+		{
+			Name:   ShiftLayer,
+			Mode:   LayerMode_MOMENTARY,
+			Keys:   append([]keycode.Code{}, cfg.ThirdLevelKey...),
+			KeyMap: Keymap{},
+		},
+		{
+			Name:         ThirdLevelLayer,
+			Mode:         LayerMode_MOMENTARY,
+			ReleaseKeys:  true,
+			Keys:         append([]keycode.Code{}, cfg.ThirdLevelKey...),
+			RequireLayer: []string{FnLayer},
+			KeyMap:       cfg.ThirdLevelKeyMap.Clone(),
+		},
+	}
+}
@@ -0,0 +1,212 @@
+package remap
+
+import (
+	"context"
+
+	"github.com/erdichen/chromekey/evdev"
+	"github.com/erdichen/chromekey/evdev/eventcode"
+	"github.com/erdichen/chromekey/evdev/keycode"
+	"github.com/erdichen/chromekey/log"
+	"github.com/erdichen/chromekey/remap/config"
+)
+
+// Status is a snapshot of the state of a remapper.
+type Status struct {
+	FnLock       bool           `json:"fn_lock"` // FN lock state of the top-level key maps.
+	Paused       bool           `json:"paused"`
+	ConfigError  bool           `json:"config_error"`
+	PressedKeys  []keycode.Code `json:"pressed_keys"`
+	ActiveLayers []string       `json:"active_layers"` // Active layers of the top-level key maps.
+	Devices      []DeviceStatus `json:"devices"`
+}
+
+// DeviceStatus is the state of an attached input device.
+type DeviceStatus struct {
+	Info         *evdev.Info `json:"info"`
+	FnLock       bool        `json:"fn_lock"`
+	ActiveLayers []string    `json:"active_layers"`
+}
+
+// Exec runs a function in the event loop of a running remapper, which owns the remapper's state.
+// The methods that read or change the state of a running remapper must be called through Exec.
+func (s *State) Exec(ctx context.Context, f func()) error {
+	done := make(chan struct{})
+	select {
+	case s.execC <- func() { f(); close(done) }:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Status returns the state of the remapper and its input devices.
+func (s *State) Status() Status {
+	top := s.profiles[len(s.profiles)-1]
+	st := Status{
+		FnLock:       top.fnLocked(),
+		Paused:       s.paused,
+		ConfigError:  s.cfgErr,
+		ActiveLayers: s.activeLayers(top),
+	}
+	var keys keycode.KeyBits
+	for _, src := range s.sources {
+		for i := range keys {
+			keys[i] |= src.inKeys[i]
+		}
+		ds := DeviceStatus{Info: src.info, FnLock: src.profile.fnLocked(), ActiveLayers: src.profile.activeLayers(&src.keys)}
+		st.Devices = append(st.Devices, ds)
+	}
+	for k := keycode.Code(0); k < keycode.Code_KEY_CNT; k++ {
+		if keys.Get(k) {
+			st.PressedKeys = append(st.PressedKeys, k)
+		}
+	}
+	return st
+}
+
+// activeLayers returns the names of the active layers of a profile given the keys held on all its input devices.
+func (s *State) activeLayers(p *profile) []string {
+	keys := s.profileKeys(p)
+	return p.activeLayers(&keys)
+}
+
+// profileKeys returns the keys held on the input devices of a profile.
+func (s *State) profileKeys(p *profile) keycode.KeyBits {
+	var keys keycode.KeyBits
+	for _, src := range s.sources {
+		if src.profile == p {
+			for i := range keys {
+				keys[i] |= src.keys[i]
+			}
+		}
+	}
+	return keys
+}
+
+// activeLayers returns the names of the active layers of a profile given the held keys.
+func (p *profile) activeLayers(keys *keycode.KeyBits) []string {
+	var names []string
+	for i, on := range p.layers.active(keys) {
+		if on {
+			names = append(names, p.layers.layers[i].cfg.Name)
+		}
+	}
+	return names
+}
+
+// toggledLayers returns the names of the TOGGLE and ONE_SHOT layers of a profile that are on.
+func (p *profile) toggledLayers() []string {
+	var names []string
+	for _, l := range p.layers.layers {
+		if l.cfg.Mode != config.LayerMode_MOMENTARY && l.on {
+			names = append(names, l.cfg.Name)
+		}
+	}
+	return names
+}
+
+// SetFnLock sets the FN lock state of all profiles. It returns false if no profile has an FN lock layer.
+func (s *State) SetFnLock(on bool) bool {
+	found := false
+	for _, p := range s.profiles {
+		if l := p.layers.get(config.FnLockLayer); l != nil {
+			l.on = on
+			found = true
+		}
+	}
+	return found
+}
+
+// Pause stops remapping keys. The input events are forwarded as they are until Resume is called.
+func (s *State) Pause() {
+	if s.paused {
+		return
+	}
+	now := s.now()
+	for _, src := range s.sources {
+		s.writeEvents(src, src.macro.stop())
+	}
+	for _, src := range s.sources {
+		// Release the remapped keys and press the held keys again without remapping.
+		keys := src.inKeys
+		s.writeEvents(src, s.syncKeys(src, &keycode.KeyBits{}, now))
+		s.writeEvents(src, genKeys(&keys, 1))
+		src.inKeys = keys
+	}
+	s.paused = true
+	s.publish(EventPaused, nil)
+}
+
+// Resume starts remapping keys again after Pause.
+func (s *State) Resume() {
+	if !s.paused {
+		return
+	}
+	s.paused = false
+	now := s.now()
+	for _, src := range s.sources {
+		// Release the held keys and press them again with remapping.
+		keys := src.inKeys
+		s.writeEvents(src, genKeys(&keys, 0))
+		src.inKeys = keycode.KeyBits{}
+		s.writeEvents(src, s.syncKeys(src, &keys, now))
+	}
+	s.publish(EventPaused, nil)
+}
+
+// passThrough forwards the input events of a paused remapper without remapping.
+func (s *State) passThrough(src *source, events []evdev.InputEvent) []evdev.InputEvent {
+	var out []evdev.InputEvent
+	for _, ev := range events {
+		if src.dropped {
+			// Discard events up to the next SYN_REPORT and then resynchronize the key states.
+			if eventcode.EventType(ev.Type) == eventcode.EV_SYN && eventcode.SynEvent(ev.Code) == eventcode.SYN_REPORT {
+				src.dropped = false
+				out = append(out, s.resync(src, s.now())...)
+			}
+			continue
+		}
+		switch eventcode.EventType(ev.Type) {
+		case eventcode.EV_KEY:
+			src.inKeys.Set(keycode.Code(ev.Code), ev.Value != 0)
+		case eventcode.EV_LED:
+			continue
+		case eventcode.EV_SYN:
+			if eventcode.SynEvent(ev.Code) == eventcode.SYN_DROPPED {
+				log.Errorf("input events dropped, resynchronizing key states")
+				src.dropped = true
+				continue
+			}
+		}
+		out = append(out, ev)
+	}
+	return out
+}
+
+// passKeys returns the key events without remapping for the keys of a source whose states differ from the given key states.
+// Released keys are sent before pressed keys.
+func passKeys(src *source, keys *keycode.KeyBits) []evdev.InputEvent {
+	var released, pressed keycode.KeyBits
+	for i := range keys {
+		released[i] = src.inKeys[i] &^ keys[i]
+		pressed[i] = keys[i] &^ src.inKeys[i]
+	}
+	src.inKeys = *keys
+	return append(genKeys(&released, 0), genKeys(&pressed, 1)...)
+}
+
+// genKeys returns the input events that press or release a set of keys.
+func genKeys(keys *keycode.KeyBits, value int32) []evdev.InputEvent {
+	var events []evdev.InputEvent
+	for k := keycode.Code_KEY_ESC; k < keycode.Code_KEY_CNT; k++ {
+		if keys.Get(k) {
+			events = append(events, GenKey(k, value)...)
+		}
+	}
+	return events
+}
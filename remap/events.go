@@ -0,0 +1,111 @@
+package remap
+
+import (
+	"strings"
+
+	"github.com/erdichen/chromekey/evdev"
+)
+
+// EventType is the kind of state change of an Event.
+type EventType string
+
+const (
+	EventStatus   EventType = "status"   // The current state sent to a new subscriber.
+	EventFnLock   EventType = "fn_lock"  // The FN lock state of the top-level key maps changed.
+	EventLayer    EventType = "layer"    // A TOGGLE or ONE_SHOT layer of any key maps was turned on or off.
+	EventAttached EventType = "attached" // An input device was attached.
+	EventDetached EventType = "detached" // An input device was detached.
+	EventPaused   EventType = "paused"   // Remapping was paused or resumed.
+	EventConfig   EventType = "config"   // The configuration or the reload error state changed.
+)
+
+// Event is a state change of a remapper with its state after the change.
+// The state leaves out the pressed keys and the momentary layers, which would reveal the timing of key presses.
+type Event struct {
+	Type   EventType   `json:"type"`
+	Device *evdev.Info `json:"device,omitempty"` // The attached or detached input device.
+	Status Status      `json:"status"`
+}
+
+// subscriberBuffer is the number of events a slow subscriber may fall behind before its oldest events are dropped.
+const subscriberBuffer = 16
+
+// Subscribe returns a channel of the state changes of the remapper, starting with its current state.
+// Call Unsubscribe to stop the events and close the channel.
+func (s *State) Subscribe() <-chan Event {
+	if s.subs == nil {
+		s.subs = map[<-chan Event]chan Event{}
+	}
+	c := make(chan Event, subscriberBuffer)
+	s.subs[c] = c
+	s.lastFnLock, s.lastLayers = s.layerState()
+	c <- Event{Type: EventStatus, Status: s.eventStatus()}
+	return c
+}
+
+// Unsubscribe stops the events of a channel returned by Subscribe and closes it.
+func (s *State) Unsubscribe(c <-chan Event) {
+	if sc, ok := s.subs[c]; ok {
+		delete(s.subs, c)
+		close(sc)
+	}
+}
+
+// publish sends an event to all subscribers.
+// Subscribers that are behind miss their oldest event so that they always receive the latest state.
+func (s *State) publish(t EventType, device *evdev.Info) {
+	if len(s.subs) == 0 {
+		return
+	}
+	ev := Event{Type: t, Device: device, Status: s.eventStatus()}
+	for _, c := range s.subs {
+		for sent := false; !sent; {
+			select {
+			case c <- ev:
+				sent = true
+			default:
+				select {
+				case <-c:
+				default:
+				}
+			}
+		}
+	}
+}
+
+// checkChanges publishes the FN lock and layer changes since the last check.
+// Nothing is checked without subscribers. Subscribe records the state to check against.
+func (s *State) checkChanges() {
+	if len(s.subs) == 0 {
+		return
+	}
+	fnLock, key := s.layerState()
+	switch {
+	case fnLock != s.lastFnLock:
+		s.publish(EventFnLock, nil)
+	case key != s.lastLayers:
+		s.publish(EventLayer, nil)
+	}
+	s.lastFnLock, s.lastLayers = fnLock, key
+}
+
+// eventStatus returns the state sent to subscribers.
+// Its active layers are only the TOGGLE and ONE_SHOT layers that are on.
+func (s *State) eventStatus() Status {
+	st := s.Status()
+	st.PressedKeys = nil
+	st.ActiveLayers = s.profiles[len(s.profiles)-1].toggledLayers()
+	for i, src := range s.sources {
+		st.Devices[i].ActiveLayers = src.profile.toggledLayers()
+	}
+	return st
+}
+
+// layerState returns the FN lock state of the top-level key maps and a key of the toggled layers of all key maps.
+func (s *State) layerState() (bool, string) {
+	var layers []string
+	for _, p := range s.profiles {
+		layers = append(layers, strings.Join(p.toggledLayers(), ","))
+	}
+	return s.profiles[len(s.profiles)-1].fnLocked(), strings.Join(layers, ";")
+}
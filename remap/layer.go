@@ -0,0 +1,173 @@
+package remap
+
+import (
+	"github.com/erdichen/chromekey/evdev/keycode"
+	"github.com/erdichen/chromekey/remap/config"
+)
+
+// layer is the runtime state of a configured key map layer.
+type layer struct {
+	cfg  config.LayerConfig
+	on   bool // Toggled state of TOGGLE and ONE_SHOT layers.
+	held int  // Number of tap-hold keys holding the layer active.
+}
+
+// layerStack holds the layers of a remapper in increasing priority order.
+type layerStack struct {
+	layers []*layer
+	index  map[string]int
+}
+
+func newLayerStack(cfgs []config.LayerConfig) layerStack {
+	ls := layerStack{index: map[string]int{}}
+	for i, c := range cfgs {
+		ls.layers = append(ls.layers, &layer{cfg: c, on: c.Enabled})
+		ls.index[c.Name] = i
+	}
+	return ls
+}
+
+// get returns the layer with the given name, or nil if there is no such layer.
+func (ls *layerStack) get(name string) *layer {
+	if i, ok := ls.index[name]; ok {
+		return ls.layers[i]
+	}
+	return nil
+}
+
+// hold activates or deactivates a layer for a held tap-hold key.
+func (ls *layerStack) hold(name string, on bool) {
+	l := ls.get(name)
+	if l == nil {
+		return
+	}
+	if on {
+		l.held++
+	} else if l.held > 0 {
+		l.held--
+	}
+}
+
+// isLayerKey returns true if key activates any layer.
+func (ls *layerStack) isLayerKey(key keycode.Code) bool {
+	for _, l := range ls.layers {
+		if l.cfg.HasKey(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// sendKey returns the keycode to send for a layer activation key.
+func (ls *layerStack) sendKey(key keycode.Code) keycode.Code {
+	for _, l := range ls.layers {
+		if l.cfg.SendKey != keycode.Code_KEY_RESERVED && l.cfg.HasKey(key) {
+			return l.cfg.SendKey
+		}
+	}
+	return key
+}
+
+// tap updates TOGGLE and ONE_SHOT layers after key has been pressed and released by itself.
+func (ls *layerStack) tap(key keycode.Code) (changed []*layer) {
+	for _, l := range ls.layers {
+		if l.cfg.Mode != config.LayerMode_MOMENTARY && l.cfg.HasKey(key) {
+			l.on = !l.on
+			changed = append(changed, l)
+		}
+	}
+	return changed
+}
+
+// active returns which layers are active given the pressed keys.
+func (ls *layerStack) active(keys *keycode.KeyBits) []bool {
+	act := make([]bool, len(ls.layers))
+	for i, l := range ls.layers {
+		switch l.cfg.Mode {
+		case config.LayerMode_MOMENTARY:
+			for _, k := range l.cfg.Keys {
+				if keys.Get(k) {
+					act[i] = true
+					break
+				}
+			}
+		default:
+			act[i] = l.on
+		}
+		act[i] = act[i] || l.held > 0
+	}
+	// Requirements are checked against the activation state before any inversion.
+	req := append([]bool{}, act...)
+	for i, l := range ls.layers {
+		for _, name := range l.cfg.RequireLayer {
+			if j, ok := ls.index[name]; !ok || !req[j] {
+				act[i] = false
+			}
+		}
+	}
+	for i, l := range ls.layers {
+		if !act[i] || l.cfg.InvertLayer == "" {
+			continue
+		}
+		if j, ok := ls.index[l.cfg.InvertLayer]; ok {
+			act[j] = !act[j]
+		}
+	}
+	return act
+}
+
+// lookup searches the active layers from the highest priority for a key action that matches the held modifiers.
+// An active layer that is not transparent hides the lower layers, except for the actions that require one of its activation keys.
+func (ls *layerStack) lookup(key keycode.Code, act []bool, keys *keycode.KeyBits) (config.KeyAction, *layer, bool) {
+	var hiding []*layer
+	for i := len(ls.layers) - 1; i >= 0; i-- {
+		if !act[i] {
+			continue
+		}
+		l := ls.layers[i]
+		for _, a := range l.cfg.KeyMap[key] {
+			if matchMods(keys, &a) && requiresLayerKeys(&a, hiding) {
+				return a, l, true
+			}
+		}
+		if !l.cfg.Transparent {
+			hiding = append(hiding, l)
+		}
+	}
+	return config.KeyAction{To: key}, nil, false
+}
+
+// requiresLayerKeys returns true if a key action requires an activation key, or its counterpart, of each of the layers.
+func requiresLayerKeys(a *config.KeyAction, layers []*layer) bool {
+	for _, l := range layers {
+		found := false
+		for _, m := range a.RequireMods {
+			found = found || l.cfg.HasKey(m) || l.cfg.HasKey(modPairs[m])
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// isModifier returns true if key is a modifier key. Modifier key presses do not use up ONE_SHOT layers.
+func isModifier(key keycode.Code) bool {
+	switch key {
+	case keycode.Code_KEY_LEFTSHIFT, keycode.Code_KEY_RIGHTSHIFT,
+		keycode.Code_KEY_LEFTCTRL, keycode.Code_KEY_RIGHTCTRL,
+		keycode.Code_KEY_LEFTALT, keycode.Code_KEY_RIGHTALT,
+		keycode.Code_KEY_LEFTMETA, keycode.Code_KEY_RIGHTMETA:
+		return true
+	}
+	return false
+}
+
+// consumeOneShot disarms the active ONE_SHOT layers after a key has been pressed with them.
+func (ls *layerStack) consumeOneShot(act []bool) {
+	for i, l := range ls.layers {
+		if act[i] && l.cfg.Mode == config.LayerMode_ONE_SHOT {
+			l.on = false
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package remap
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/erdichen/chromekey/evdev"
+	"github.com/erdichen/chromekey/evdev/eventcode"
+	"github.com/erdichen/chromekey/evdev/keycode"
+	"github.com/erdichen/chromekey/log"
+	"github.com/erdichen/chromekey/remap/config"
+	"github.com/erdichen/chromekey/uinput"
+)
+
+// startLEDLoop loops reading the LED events that the desktop sends to a virtual keyboard and sends them to the remapper.
+// The loop stops when the virtual keyboard is closed.
+func (s *State) startLEDLoop(ctx context.Context, out *uinput.Device) {
+	go func() {
+		for {
+			events, err := out.ReadEvents()
+			if err != nil {
+				if !errors.Is(err, os.ErrClosed) {
+					log.Errorf("failed to read from uinput device: %v", err)
+				}
+				return
+			}
+			select {
+			case s.ledC <- events:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// mirrorLEDs sets the LEDs of all the input devices to the LED states set by the desktop on any virtual keyboard.
+// The LEDs bound to remapper states are reserved for updateLEDs.
+func (s *State) mirrorLEDs(events []evdev.InputEvent) {
+	for _, ev := range events {
+		if eventcode.EventType(ev.Type) != eventcode.EV_LED || ev.Code >= uint16(keycode.LED_CNT) {
+			continue
+		}
+		led, on := keycode.LED(ev.Code), ev.Value != 0
+		if verbosity > 1 {
+			log.Infof("LED %v %v", led, on)
+		}
+		s.leds[led] = on
+		for _, src := range s.sources {
+			if !src.profile.isBound(led) {
+				setLED(src, led, on)
+			}
+		}
+	}
+}
+
+// setLEDs sets all the LEDs of a source after it is attached or its profile has changed.
+// The LEDs no longer bound to remapper states and never set by the desktop are turned off.
+func (s *State) setLEDs(src *source) {
+	for led := range src.leds {
+		if _, ok := s.leds[led]; !ok && !src.profile.isBound(led) {
+			setLED(src, led, false)
+		}
+	}
+	for led, on := range s.leds {
+		if !src.profile.isBound(led) {
+			setLED(src, led, on)
+		}
+	}
+	src.leds = nil
+	s.updateLEDs()
+}
+
+// SetConfigError sets the state shown by the CONFIG_ERROR LEDs.
+func (s *State) SetConfigError(failed bool) {
+	if s.cfgErr != failed {
+		s.cfgErr = failed
+		s.publish(EventConfig, nil)
+	}
+	s.updateLEDs()
+}
+
+// resetLEDTimer updates the LEDs bound to remapper states and sets a timer to fire at the next blink.
+func (s *State) resetLEDTimer(t *time.Timer) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+	if d, ok := s.updateLEDs(); ok {
+		t.Reset(d)
+	}
+}
+
+// updateLEDs sets the LEDs bound to remapper states that have changed.
+// It returns the time until the next blink if any LED is blinking.
+func (s *State) updateLEDs() (time.Duration, bool) {
+	now := s.now()
+	var next time.Duration
+	blinking := false
+	for _, p := range s.profiles {
+		if len(p.leds) == 0 {
+			continue
+		}
+		lit := map[keycode.LED]bool{}
+		for _, b := range p.leds {
+			on := s.isActive(p, b)
+			if on && b.Pattern == config.LEDPattern_BLINK {
+				half := b.BlinkPeriod() / 2
+				phase := time.Duration(now.UnixNano()) % (2 * half)
+				on = phase < half
+				if d := half - phase%half; !blinking || d < next {
+					next, blinking = d, true
+				}
+			}
+			lit[b.LED] = lit[b.LED] || on
+		}
+		for _, src := range s.sources {
+			if src.profile != p {
+				continue
+			}
+			if src.leds == nil {
+				src.leds = map[keycode.LED]bool{}
+			}
+			for led, on := range lit {
+				if v, ok := src.leds[led]; !ok || v != on {
+					src.leds[led] = on
+					setLED(src, led, on)
+				}
+			}
+		}
+	}
+	return next, blinking
+}
+
+// isActive returns true if the state of an LED binding is active in a profile.
+func (s *State) isActive(p *profile, b config.LEDBindingConfig) bool {
+	switch b.State {
+	case config.LEDState_FN_LOCK:
+		return p.fnLocked()
+	case config.LEDState_LAYER_ACTIVE:
+		i, ok := p.layers.index[b.Layer]
+		keys := s.profileKeys(p)
+		return ok && p.layers.active(&keys)[i]
+	case config.LEDState_MACRO_PLAYING:
+		for _, src := range s.sources {
+			if _, ok := src.macro.deadline(); ok && src.profile == p {
+				return true
+			}
+		}
+	case config.LEDState_CONFIG_ERROR:
+		return s.cfgErr
+	}
+	return false
+}
+
+// isBound returns true if an LED is bound to a remapper state of a profile.
+func (p *profile) isBound(led keycode.LED) bool {
+	for _, b := range p.leds {
+		if b.LED == led {
+			return true
+		}
+	}
+	return false
+}
+
+// setLED sets an LED of the input device of a source.
+func setLED(src *source, led keycode.LED, on bool) {
+	if err := src.ledOut.SetLED(led, on); err != nil {
+		log.Errorf("failed to set evdev device LED %v: %v", led, err)
+	}
+}
@@ -0,0 +1,77 @@
+package remap
+
+import (
+	"time"
+
+	"github.com/erdichen/chromekey/evdev"
+	"github.com/erdichen/chromekey/evdev/keycode"
+	"github.com/erdichen/chromekey/remap/config"
+)
+
+// macroPlayer plays the steps of a macro. Delay steps are resumed by the key timer.
+type macroPlayer struct {
+	trigger keycode.Code // The key that started the macro.
+	steps   []config.MacroStepConfig
+	next    time.Time       // Time to resume after a delay step.
+	pressed keycode.KeyBits // Keys pressed by the macro and not yet released.
+}
+
+// deadline returns when a delayed macro resumes playing.
+func (m *macroPlayer) deadline() (time.Time, bool) {
+	if len(m.steps) == 0 {
+		return time.Time{}, false
+	}
+	return m.next, true
+}
+
+// start plays a macro triggered by a key. Pressing the trigger key of a playing macro stops it.
+func (m *macroPlayer) start(trigger keycode.Code, steps []config.MacroStepConfig, now time.Time) []evdev.InputEvent {
+	playing := len(m.steps) > 0
+	events := m.stop()
+	if playing && trigger == m.trigger {
+		return events
+	}
+	m.trigger = trigger
+	m.steps = steps
+	m.next = now
+	return append(events, m.play(now)...)
+}
+
+// stop stops a playing macro and releases the keys it has pressed.
+func (m *macroPlayer) stop() []evdev.InputEvent {
+	var events []evdev.InputEvent
+	for k := keycode.Code_KEY_ESC; k < keycode.Code_KEY_CNT; k++ {
+		if m.pressed.Get(k) {
+			events = append(events, GenKey(k, 0)...)
+		}
+	}
+	m.pressed = keycode.KeyBits{}
+	m.steps = nil
+	return events
+}
+
+// play runs the macro steps until the next delay step or the end of the macro.
+func (m *macroPlayer) play(now time.Time) []evdev.InputEvent {
+	var events []evdev.InputEvent
+	for len(m.steps) > 0 {
+		step := m.steps[0]
+		m.steps = m.steps[1:]
+		switch step.Action {
+		case config.MacroAction_TAP:
+			events = append(events, GenKey(step.Key, 1)...)
+			events = append(events, GenKey(step.Key, 0)...)
+		case config.MacroAction_PRESS:
+			m.pressed.Set(step.Key, true)
+			events = append(events, GenKey(step.Key, 1)...)
+		case config.MacroAction_RELEASE:
+			m.pressed.Set(step.Key, false)
+			events = append(events, GenKey(step.Key, 0)...)
+		case config.MacroAction_DELAY:
+			if len(m.steps) > 0 {
+				m.next = now.Add(step.Delay)
+				return events
+			}
+		}
+	}
+	return events
+}
@@ -0,0 +1,57 @@
+package remap
+
+import (
+	"github.com/erdichen/chromekey/evdev/keycode"
+	"github.com/erdichen/chromekey/remap/config"
+)
+
+// modPairs maps each modifier key to the same modifier on the other side of the keyboard.
+var modPairs = map[keycode.Code]keycode.Code{
+	keycode.Code_KEY_LEFTSHIFT:  keycode.Code_KEY_RIGHTSHIFT,
+	keycode.Code_KEY_RIGHTSHIFT: keycode.Code_KEY_LEFTSHIFT,
+	keycode.Code_KEY_LEFTCTRL:   keycode.Code_KEY_RIGHTCTRL,
+	keycode.Code_KEY_RIGHTCTRL:  keycode.Code_KEY_LEFTCTRL,
+	keycode.Code_KEY_LEFTALT:    keycode.Code_KEY_RIGHTALT,
+	keycode.Code_KEY_RIGHTALT:   keycode.Code_KEY_LEFTALT,
+	keycode.Code_KEY_LEFTMETA:   keycode.Code_KEY_RIGHTMETA,
+	keycode.Code_KEY_RIGHTMETA:  keycode.Code_KEY_LEFTMETA,
+}
+
+// isModHeld returns true if a modifier key or its counterpart on the other side is held.
+func isModHeld(keys *keycode.KeyBits, mod keycode.Code) bool {
+	if keys.Get(mod) {
+		return true
+	}
+	pair, ok := modPairs[mod]
+	return ok && keys.Get(pair)
+}
+
+// heldMods returns the held keys of a list of keys, including the held counterparts of modifier keys.
+func heldMods(keys *keycode.KeyBits, mods []keycode.Code) []keycode.Code {
+	var held []keycode.Code
+	var seen keycode.KeyBits
+	for _, k := range mods {
+		for _, c := range []keycode.Code{k, modPairs[k]} {
+			if c != keycode.Code_KEY_RESERVED && keys.Get(c) && !seen.Get(c) {
+				seen.Set(c, true)
+				held = append(held, c)
+			}
+		}
+	}
+	return held
+}
+
+// matchMods returns true if the modifier conditions of a key action are met.
+func matchMods(keys *keycode.KeyBits, a *config.KeyAction) bool {
+	for _, k := range a.RequireMods {
+		if !isModHeld(keys, k) {
+			return false
+		}
+	}
+	for _, k := range a.ForbidMods {
+		if isModHeld(keys, k) {
+			return false
+		}
+	}
+	return true
+}
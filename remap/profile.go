@@ -0,0 +1,63 @@
+package remap
+
+import (
+	"github.com/erdichen/chromekey/evdev"
+	"github.com/erdichen/chromekey/log"
+	"github.com/erdichen/chromekey/remap/config"
+)
+
+// profile is the key map state of the input devices that match a device section.
+// The input devices of a profile share its layers. Each input device has its own key states.
+type profile struct {
+	matcher  *evdev.Matcher // Nil if the device section is invalid.
+	fallback bool
+	cfg      config.RunConfig
+
+	layers layerStack
+	leds   []config.LEDBindingConfig
+}
+
+func newProfile(d config.DeviceConfig) *profile {
+	m, err := d.Match.Matcher()
+	if err != nil {
+		log.Errorf("invalid device section: %v", err)
+	}
+	return &profile{
+		matcher:  m,
+		fallback: d.Fallback,
+		cfg:      d.Config,
+		layers:   newLayerStack(d.Config.EffectiveLayers()),
+		leds:     d.Config.EffectiveLEDs(),
+	}
+}
+
+// fnLocked returns true if the FN lock layer is toggled on.
+func (p *profile) fnLocked() bool {
+	l := p.layers.get(config.FnLockLayer)
+	return l != nil && l.on
+}
+
+// profileOf returns the profile of the first device section that matches an input device.
+// An input device that matches no section uses the fallback section or else the top-level configuration.
+func (s *State) profileOf(in *evdev.Device) *profile {
+	top := s.profiles[len(s.profiles)-1]
+	if in == nil {
+		return top
+	}
+	var fallback *profile
+	for _, p := range s.profiles[:len(s.profiles)-1] {
+		if p.fallback {
+			if fallback == nil {
+				fallback = p
+			}
+			continue
+		}
+		if p.matcher != nil && p.matcher.Match(in) {
+			return p
+		}
+	}
+	if fallback != nil {
+		return fallback
+	}
+	return top
+}
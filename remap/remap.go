@@ -17,63 +17,125 @@ import (
 
 // State is the data of a key remapper that simulates the FN key that can remap function keys to media keys.
 type State struct {
-	in  *evdev.Device
-	out *uinput.Device
-	evC chan []evdev.InputEvent
+	sources  []*source
+	out      *uinput.Device // The shared virtual keyboard, nil if each source has its own virtual keyboard.
+	opts     Options
+	evC      chan sourceEvents
+	hotplugC chan string             // Paths of new input devices, nil if hotplug is disabled.
+	ledC     chan []evdev.InputEvent // LED events sent to the virtual keyboards by the desktop.
+	leds     map[keycode.LED]bool    // LED states set by the desktop.
+	cfgErr   bool                    // The last configuration reload failed.
+	execC    chan func()             // Functions run in the event loop by Exec.
+	paused   bool                    // Forward input events without remapping.
 
-	fnEnable bool
-	lastKey  keycode.Code
-	keys     keycode.KeyBits
+	subs       map[<-chan Event]chan Event // Subscribers of state changes.
+	lastFnLock bool                        // FN lock state at the last checkChanges.
+	lastLayers string                      // Active layers at the last checkChanges.
+	profiles   []*profile                  // Key maps of the device sections, the last profile is the top-level configuration.
+	now        func() time.Time
 
 	cfg config.RunConfig
 }
 
-// New returns new a key remapper.
-func New(ctx context.Context, in *evdev.Device, outputDev string, cfg config.RunConfig, grab bool) (*State, error) {
-	ok := false
+// Options are the settings of a remapper that don't change after it is created.
+type Options struct {
+	OutputDev   string // The uinput device.
+	Grab        bool   // Grab the input devices.
+	WaitRelease bool   // Wait for all keys to be released before grabbing an input device, otherwise send the pressed keys to the virtual keyboard.
+
+	// Watch DevDir for new input devices and attach those selected by Match.
+	DevDir string
+	Match  func(d *evdev.Device) bool
+}
+
+// New returns new a key remapper that reads from one or more input devices.
+func New(ctx context.Context, ins []*evdev.Device, cfg config.RunConfig, opts Options) (*State, error) {
+	s := &State{
+		opts:  opts,
+		evC:   make(chan sourceEvents),
+		ledC:  make(chan []evdev.InputEvent),
+		leds:  map[keycode.LED]bool{},
+		execC: make(chan func()),
+		now:   time.Now,
+	}
+	s.SetConfig(cfg)
 
+	ok := false
 	defer func() {
 		if !ok {
-			in.Close()
+			for _, in := range ins {
+				in.Close()
+			}
+			s.Close()
 		}
 	}()
 
-	// Grabbing an input device will cause any pressed key to stuck in the pressed state.
-	if err := waitForAllKeysReleased(in); err != nil {
-		return nil, err
+	if cfg.OutputMode == config.OutputMode_SHARED_OUTPUT {
+		// Create an virtual device that supports the capabilities of all the input devices.
+		// Input devices attached later may have any key.
+		b := uinput.NewBuilder().AddRemapCapabilities()
+		if opts.Match != nil {
+			for k := keycode.Code_KEY_ESC; k < keycode.Code_KEY_MAX; k++ {
+				b.AddKeys(k)
+			}
+		}
+		for _, in := range ins {
+			c, err := in.GetCapabilities()
+			if err != nil {
+				log.Errorf("failed to get capabilities from evdev device: %v", err)
+				return nil, err
+			}
+			b.AddCapabilities(c)
+		}
+		var src *evdev.Device
+		if len(ins) > 0 {
+			src = ins[0]
+		}
+		if err := s.setIdentity(b, src); err != nil {
+			return nil, err
+		}
+		out, err := b.Create(opts.OutputDev)
+		if err != nil {
+			return nil, err
+		}
+		s.out = out
+		s.startLEDLoop(ctx, out)
 	}
 
-	if grab {
-		if err := in.Grab(); err != nil {
+	for len(ins) > 0 {
+		in := ins[0]
+		ins = ins[1:]
+		if err := s.attach(ctx, in, opts.WaitRelease); err != nil {
 			return nil, err
 		}
 	}
 
-	// Create an virtual device that replicates the capabilities and keys of the give input device.
-	out, err := uinput.CreateFromDevice(outputDev, in)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if !ok {
-			out.Close()
+	if opts.Match != nil {
+		c, err := evdev.WatchDir(ctx, opts.DevDir)
+		if err != nil {
+			return nil, err
 		}
-	}()
+		s.hotplugC = c
+	}
 
 	ok = true
-	return &State{in: in, out: out, fnEnable: cfg.FnEnabled, cfg: cfg}, nil
+	return s, nil
 }
 
 // Close closes a remapper and its input and output devices.
 func (s *State) Close() error {
-	if err := s.in.Ungrab(); err != nil {
-		return err
+	var errs []error
+	for len(s.sources) > 0 {
+		errs = append(errs, s.detach(s.sources[0]))
 	}
-	if err := s.in.Close(); err != nil {
-		return err
+	if s.out != nil {
+		errs = append(errs, s.out.Close())
+		s.out = nil
 	}
-	if err := s.out.Close(); err != nil {
-		return err
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -84,21 +146,36 @@ func (s *State) Config() config.RunConfig {
 }
 
 // SetConfig load a RunConfig into a remapper's internal state.
+// The attached input devices switch to the profiles of the new device sections.
 func (s *State) SetConfig(cfg config.RunConfig) {
 	s.cfg = cfg.Clone()
-	s.fnEnable = cfg.FnEnabled
+	s.profiles = nil
+	for _, d := range cfg.Devices {
+		s.profiles = append(s.profiles, newProfile(d))
+	}
+	s.profiles = append(s.profiles, newProfile(config.DeviceConfig{Config: cfg}))
+	for _, src := range s.sources {
+		// The key states of the old key maps do not carry over.
+		s.writeEvents(src, src.release(s.now()))
+		src.setProfile(s.profileOf(src.in))
+		s.setLEDs(src)
+	}
+	s.publish(EventConfig, nil)
 }
 
 // Start runs the execution loop that forwards input events from the real keyboard to the virtual keyboard, remapping keys when necessary.
-func (s *State) Start(ctx context.Context, sigC chan os.Signal, evC chan []evdev.InputEvent, timeout time.Duration) error {
+func (s *State) Start(ctx context.Context, sigC chan os.Signal, timeout time.Duration) error {
 	t := time.NewTimer(timeout)
 	if timeout == 0 {
 		t.Stop()
 	}
 
-	// Wait for the system to respond to the new input device.
-	ledTimer := time.NewTimer(2 * time.Second)
-	again := true
+	// Fires when an undecided key event times out.
+	keyTimer := time.NewTimer(0)
+	<-keyTimer.C
+
+	// Fires when a blinking LED changes, and at once to set the initial LED states.
+	ledTimer := time.NewTimer(0)
 
 	done := false
 	for !done {
@@ -115,23 +192,44 @@ func (s *State) Start(ctx context.Context, sigC chan os.Signal, evC chan []evdev
 			done = true
 		case <-t.C:
 			done = true
+		case events := <-s.ledC:
+			s.mirrorLEDs(events)
 		case <-ledTimer.C:
-			s.setFnLED()
-			if again {
-				// Set twice in case the desktop envinrment's state is out of sync with the hardware.
-				again = false
-				ledTimer.Reset(250 * time.Millisecond)
+			s.resetLEDTimer(ledTimer)
+		case f := <-s.execC:
+			f()
+			s.resetKeyTimer(keyTimer)
+			s.resetLEDTimer(ledTimer)
+			s.checkChanges()
+		case <-keyTimer.C:
+			if !s.paused {
+				now := s.now()
+				for _, src := range s.sources {
+					s.writeEvents(src, src.handleTimeout(now))
+				}
 			}
-		case events, ok := <-evC:
+			s.resetKeyTimer(keyTimer)
+			s.resetLEDTimer(ledTimer)
+			s.checkChanges()
+		case path, ok := <-s.hotplugC:
 			if !ok {
-				done = true
+				s.hotplugC = nil
+				done = len(s.sources) == 0
 				break
 			}
-			events = s.handleEvents(events)
-			if err := s.out.WriteEvents(events); err != nil {
-				log.Errorf("failed to write events to uinput device: %v", err)
+			s.hotplug(ctx, path)
+		case se := <-s.evC:
+			if se.events == nil {
+				if err := s.detach(se.src); err != nil {
+					log.Errorf("failed to detach input device: %v", err)
+				}
+				done = len(s.sources) == 0 && s.hotplugC == nil
 				break
 			}
+			s.writeEvents(se.src, s.handleEvents(se.src, se.events))
+			s.resetKeyTimer(keyTimer)
+			s.resetLEDTimer(ledTimer)
+			s.checkChanges()
 			if timeout > 0 {
 				t.Reset(timeout)
 			}
@@ -140,6 +238,75 @@ func (s *State) Start(ctx context.Context, sigC chan os.Signal, evC chan []evdev
 	return nil
 }
 
+// writeEvents writes input events to the virtual keyboard of a source.
+func (s *State) writeEvents(src *source, events []evdev.InputEvent) {
+	if len(events) == 0 || src == nil {
+		return
+	}
+	if err := src.out.WriteEvents(events); err != nil {
+		log.Errorf("failed to write events to uinput device: %v", err)
+	}
+}
+
+// resetKeyTimer sets a timer to fire at the next key event timeout. The timer is stopped while paused.
+func (s *State) resetKeyTimer(t *time.Timer) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+	if d, ok := s.deadline(); ok && !s.paused {
+		t.Reset(d.Sub(s.now()))
+	}
+}
+
+// deadline returns the time of the earliest key event timeout of all sources.
+func (s *State) deadline() (time.Time, bool) {
+	var d time.Time
+	ok := false
+	for _, src := range s.sources {
+		if sd, sok := src.deadline(); sok && (!ok || sd.Before(d)) {
+			d, ok = sd, sok
+		}
+	}
+	return d, ok
+}
+
+// deadline returns the time of the earliest key event timeout.
+func (src *source) deadline() (time.Time, bool) {
+	d, ok := src.combo.deadline()
+	for _, f := range []func() (time.Time, bool){src.tapHold.deadline, src.macro.deadline} {
+		if td, tok := f(); tok && (!ok || td.Before(d)) {
+			d, ok = td, tok
+		}
+	}
+	return d, ok
+}
+
+// handleTimeout resolves the key events that are undecided past their timeout.
+func (src *source) handleTimeout(now time.Time) []evdev.InputEvent {
+	var events []evdev.InputEvent
+	if d, ok := src.combo.deadline(); ok && !now.Before(d) {
+		events = append(events, src.checkCombo(now, true)...)
+	}
+	if d, ok := src.tapHold.deadline(); ok && !now.Before(d) {
+		events = append(events, src.resolveTapHold(true, now)...)
+	}
+	if d, ok := src.macro.deadline(); ok && !now.Before(d) {
+		events = append(events, src.macro.play(now)...)
+	}
+	return events
+}
+
+// release resolves the pending keys of a source and releases the outputs of its held tap-hold keys, combos and macro.
+// The other pressed keys are released with their outputs when their input keys are released.
+func (src *source) release(now time.Time) []evdev.InputEvent {
+	events := src.releaseCombos(now)
+	events = append(events, src.releaseTapHold(now)...)
+	return append(events, src.macro.stop()...)
+}
+
 var verbosity = 0
 
 // SetVerbosity sets logging verbosity.
@@ -167,130 +334,209 @@ func GenKey(key keycode.Code, value int32) []evdev.InputEvent {
 	}
 }
 
-// setFnLED uses one the keyboard's LEDs to indicate FN key lock.
-func (s *State) setFnLED() {
-	key := keycode.Code_KEY_RESERVED
-	switch s.cfg.UseLED {
-	case keycode.LED_NUML:
-		key = keycode.Code_KEY_NUMLOCK
-	case keycode.LED_CAPSL:
-		key = keycode.Code_KEY_CAPSLOCK
-	case keycode.LED_SCROLLL:
-		key = keycode.Code_KEY_SCROLLLOCK
-	case keycode.LED_COMPOSE:
-		key = keycode.Code_KEY_COMPOSE
-	default:
-		return
+// handleEvents converts key events to mapped key events if it matches the mapping rules.
+func (s *State) handleEvents(src *source, events []evdev.InputEvent) []evdev.InputEvent {
+	if s.paused {
+		return s.passThrough(src, events)
+	}
+	now := s.now()
+	var out []evdev.InputEvent
+	for _, ev := range events {
+		if verbosity > 1 {
+			fmt.Printf("%s\n", ev.String())
+		}
+		if src.dropped {
+			// Discard events up to the next SYN_REPORT and then resynchronize the key states.
+			if eventcode.EventType(ev.Type) == eventcode.EV_SYN && eventcode.SynEvent(ev.Code) == eventcode.SYN_REPORT {
+				src.dropped = false
+				out = append(out, s.resync(src, now)...)
+			}
+			continue
+		}
+		switch eventcode.EventType(ev.Type) {
+		case eventcode.EV_KEY:
+			src.inKeys.Set(keycode.Code(ev.Code), ev.Value != 0)
+			out = append(out, src.processCombo(ev, now)...)
+		case eventcode.EV_MSC:
+			// Scancodes of key events are regenerated with the mapped keys.
+			if eventcode.MiscEvent(ev.Code) != eventcode.MSC_SCAN {
+				out = append(out, ev)
+			}
+		case eventcode.EV_LED:
+			// LED events echo the LED states set by the remapper.
+		case eventcode.EV_SYN:
+			if eventcode.SynEvent(ev.Code) == eventcode.SYN_DROPPED {
+				log.Errorf("input events dropped, resynchronizing key states")
+				src.dropped = true
+				continue
+			}
+			// Skip empty reports left by key events that have been buffered or already reported.
+			if eventcode.SynEvent(ev.Code) != eventcode.SYN_REPORT || !isReported(out) {
+				out = append(out, ev)
+			}
+		default:
+			out = append(out, ev)
+		}
 	}
+	return out
+}
 
-	leds, err := s.in.GetLED()
-	if err != nil {
-		log.Errorf("failed get evdev device LED status: %v", err)
-		return
+// resync reads the key states of an input device and sends key events for the key state changes that were dropped.
+// A paused remapper sends them without remapping.
+func (s *State) resync(src *source, now time.Time) []evdev.InputEvent {
+	var keys keycode.KeyBits
+	if err := src.in.GetKeyStates(keys[:]); err != nil {
+		log.Errorf("failed to get evdev device key states: %v", err)
+		return nil
 	}
-	if leds[s.cfg.UseLED] == s.fnEnable {
-		return
+	if s.paused {
+		return passKeys(src, &keys)
 	}
+	return s.syncKeys(src, &keys, now)
+}
 
-	v := 0
-	if s.fnEnable {
-		v = 1
+// syncKeys sends synthetic key events for the keys of a source whose states differ from the given key states.
+// Released keys are sent before pressed keys.
+func (s *State) syncKeys(src *source, keys *keycode.KeyBits, now time.Time) []evdev.InputEvent {
+	var events []evdev.InputEvent
+	for _, down := range []bool{false, true} {
+		for k := keycode.Code_KEY_ESC; k < keycode.Code_KEY_CNT; k++ {
+			if keys.Get(k) != down || src.inKeys.Get(k) == down {
+				continue
+			}
+			if verbosity > 0 {
+				log.Infof("resync %v %v", k, down)
+			}
+			ev := evdev.InputEvent{Type: uint16(eventcode.EV_KEY), Code: uint16(k)}
+			if down {
+				ev.Value = 1
+			}
+			src.inKeys.Set(k, down)
+			events = append(events, src.processCombo(ev, now)...)
+		}
 	}
+	return events
+}
 
-	events := GenKey(key, 1)
-	events = append(events, GenKey(key, 0)...)
-	events = append(events, evdev.InputEvent{
-		Type:  uint16(eventcode.EV_LED),
-		Code:  uint16(keycode.LED_NUML),
-		Value: int32(v),
-	})
-	if err := s.out.WriteEvents(events); err != nil {
-		log.Errorf("failed to write num lock key events: %v", err)
+// isReported returns true if a list of events is empty or ends with a SYN_REPORT.
+func isReported(events []evdev.InputEvent) bool {
+	if len(events) == 0 {
+		return true
 	}
+	last := events[len(events)-1]
+	return eventcode.EventType(last.Type) == eventcode.EV_SYN && eventcode.SynEvent(last.Code) == eventcode.SYN_REPORT
+}
+
+// pressedKey is the output of a key while it is pressed.
+type pressedKey struct {
+	action   config.KeyAction
+	suppress []keycode.Code
 }
 
-// isThirdLevelKeyDown returns true if any third level key is down.
-func (s *State) isThirdLevelKeyDown() bool {
-	for _, k := range s.cfg.ThirdLevelKey {
-		if s.keys.Get(k) {
-			return true
+// mapKey converts a key event to mapped key events using the active layers.
+// Repeats and releases of a key always send the output of its key press, even if the active layers have changed.
+func (src *source) mapKey(ev evdev.InputEvent, now time.Time) []evdev.InputEvent {
+	key := keycode.Code(ev.Code)
+	src.keys.Set(key, ev.Value != 0)
+	defer func() { src.lastKey = key }()
+
+	// Toogle layers only if the key is pressed by itself.
+	// Ignore these two cases:
+	//   1. The key is last key released, but another key was released while it is down.
+	//   2. The key released with at least 1 key still down, including the held dual-role keys.
+	if ev.Value == 0 && src.lastKey == key && src.keys.IsZero() && !src.tapHold.isHeld() {
+		for _, l := range src.profile.layers.tap(key) {
+			if verbosity > 0 {
+				log.Infof("layer %s %v", l.cfg.Name, l.on)
+			}
 		}
 	}
-	return false
-}
 
-// handleEvents converts key events to mapped key events if it matches the mapping rules.
-func (s *State) handleEvents(events []evdev.InputEvent) []evdev.InputEvent {
-	var pre, post []evdev.InputEvent
-	for i, ev := range events {
-		if verbosity > 1 {
-			fmt.Printf("%s\n", ev.String())
+	if src.pressed == nil {
+		src.pressed = map[keycode.Code]pressedKey{}
+	}
+	pk, ok := src.pressed[key]
+	if ev.Value == 0 {
+		delete(src.pressed, key)
+	}
+	if !ok || ev.Value == 1 {
+		pk = src.lookupKey(key, ev.Value == 1)
+		if ev.Value == 1 {
+			src.pressed[key] = pk
 		}
-		switch eventcode.EventType(ev.Type) {
-		case eventcode.EV_KEY:
-			s.keys.Set(keycode.Code(ev.Code), ev.Value != 0)
-			switch keycode.Code(ev.Code) {
-			case s.cfg.FnKey:
-				// Toogle FN key lock state only if it is pressed by itself.
-				// Ignore these two cases:
-				//   1. FN is last key released, but another key was released while FN is down.
-				//   2. FN released with at least 1 key still down.
-				if ev.Value == 0 && s.lastKey == s.cfg.FnKey && s.keys.IsZero() {
-					s.fnEnable = !s.fnEnable
-					if verbosity > 0 {
-						log.Infof("FN %v", s.fnEnable)
-					}
-					s.setFnLED()
-				}
-				events[i].Code = uint16(keycode.Code_KEY_FN)
-			default:
-				if s.isThirdLevelKeyDown() {
-					// Handle FN+ThirdLevelKey+ key map.
-					if key, ok := s.cfg.ThirdLevelKeyMap[keycode.Code(ev.Code)]; ok {
-						fnDown := s.keys.Get(s.cfg.FnKey)
-						if fnDown {
-							// Clear the third level keys to simulate the mapped key with the third level keys released.
-							for _, k := range s.cfg.ThirdLevelKey {
-								if s.keys.Get(k) {
-									pre = append(pre, GenKey(k, 0)...)
-									post = append(post, GenKey(k, 1)...)
-								}
-							}
-							events[i].Code = uint16(key)
-							if verbosity > 0 {
-								log.Infof("third level map %v to %v", keycode.Code(ev.Code), key)
-							}
-						}
-					}
-				} else if s.keys.Get(s.cfg.FnKey) {
-					if key, ok := s.cfg.ModKeyMap[keycode.Code(ev.Code)]; ok {
-						events[i].Code = uint16(key)
-						if verbosity > 0 {
-							log.Infof("mod map %v to %v", keycode.Code(ev.Code), key)
-						}
-					} else if key, ok := s.cfg.KeyMap[keycode.Code(ev.Code)]; ok && !s.fnEnable {
-						events[i].Code = uint16(key)
-						if verbosity > 0 {
-							log.Infof("fn mod map %v to %v", keycode.Code(ev.Code), key)
-						}
-					}
-				} else if key, ok := s.cfg.KeyMap[keycode.Code(ev.Code)]; ok {
-					if s.fnEnable {
-						events[i].Code = uint16(key)
-						if verbosity > 0 {
-							log.Infof("fn map %v to %v", keycode.Code(ev.Code), key)
-						}
-					}
-				}
+		if len(pk.action.Macro) > 0 {
+			if ev.Value != 1 {
+				return nil
 			}
-			s.lastKey = keycode.Code(ev.Code)
+			return src.macro.start(key, pk.action.Macro, now)
 		}
 	}
-	if pre != nil {
-		events = append(pre, events...)
+	if len(pk.action.Macro) > 0 {
+		return nil
+	}
+	return src.genChord(pk.action.To, ev.Value, pk.action.AddMods, pk.suppress)
+}
+
+// lookupKey returns the output of a key given the active layers.
+// A key press uses up the active one-shot layers, unless the key is a modifier or a layer key.
+func (src *source) lookupKey(key keycode.Code, press bool) pressedKey {
+	if src.profile.layers.isLayerKey(key) {
+		return pressedKey{action: config.KeyAction{To: src.profile.layers.sendKey(key)}}
+	}
+
+	keys := src.modKeys()
+	act := src.profile.layers.active(keys)
+	if press && !isModifier(key) {
+		defer src.profile.layers.consumeOneShot(act)
 	}
-	if post != nil {
-		events = append(events, post...)
+	a, l, ok := src.profile.layers.lookup(key, act, keys)
+	if !ok {
+		return pressedKey{action: a}
+	}
+	if verbosity > 0 {
+		if len(a.Macro) > 0 {
+			log.Infof("layer %s map %v to macro", l.cfg.Name, key)
+		} else {
+			log.Infof("layer %s map %v to %v", l.cfg.Name, key, a.To)
+		}
+	}
+	pk := pressedKey{action: a, suppress: a.SuppressMods}
+	if l.cfg.ReleaseKeys {
+		pk.suppress = append(append([]keycode.Code{}, pk.suppress...), l.cfg.Keys...)
+	}
+	return pk
+}
+
+// genChord returns input events that press or release a key with modifiers.
+// The added modifiers are pressed and the held suppressed modifiers are released while the key is held.
+// A suppressed modifier also releases its counterpart on the other side.
+func (src *source) genChord(key keycode.Code, value int32, add, suppress []keycode.Code) []evdev.InputEvent {
+	var events []evdev.InputEvent
+	switch value {
+	case 1:
+		for _, k := range heldMods(src.modKeys(), suppress) {
+			events = append(events, GenKey(k, 0)...)
+		}
+		for _, k := range add {
+			events = append(events, GenKey(k, 1)...)
+		}
+		if key != keycode.Code_KEY_RESERVED {
+			events = append(events, GenKey(key, 1)...)
+		}
+	case 0:
+		if key != keycode.Code_KEY_RESERVED {
+			events = append(events, GenKey(key, 0)...)
+		}
+		for i := len(add) - 1; i >= 0; i-- {
+			events = append(events, GenKey(add[i], 0)...)
+		}
+		for _, k := range heldMods(src.modKeys(), suppress) {
+			events = append(events, GenKey(k, 1)...)
+		}
+	default:
+		if key != keycode.Code_KEY_RESERVED {
+			events = append(events, GenKey(key, value)...)
+		}
 	}
 	return events
 }
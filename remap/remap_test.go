@@ -1,5 +1,835 @@
 package remap
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/erdichen/chromekey/evdev"
+	"github.com/erdichen/chromekey/evdev/eventcode"
+	"github.com/erdichen/chromekey/evdev/keycode"
+	"github.com/erdichen/chromekey/remap/config"
+)
 
 func TestRemap(t *testing.T) {}
+
+// testClock is a fake clock advanced by the test key sequences.
+type testClock struct {
+	t time.Time
+}
+
+func (c *testClock) now() time.Time {
+	return c.t
+}
+
+// newTestState returns a remapper without input and output devices.
+func newTestState(cfg config.RunConfig) (*State, *testClock) {
+	cfg.UseLED = keycode.LED_CNT
+	clk := &testClock{t: time.Unix(1000, 0)}
+	s := &State{now: clk.now, sources: []*source{{ledOut: testLEDs{}}}}
+	s.SetConfig(cfg)
+	return s, clk
+}
+
+// keyEvent is a key event of a test sequence. A zero key waits for the value in milliseconds and runs the key timer.
+type keyEvent struct {
+	key   keycode.Code
+	value int32
+}
+
+// wait returns an event that advances the test clock.
+func wait(ms int32) []keyEvent {
+	return []keyEvent{{keycode.Code_KEY_RESERVED, ms}}
+}
+
+// press returns key events that press a list of keys in order.
+func press(keys ...keycode.Code) []keyEvent {
+	var evs []keyEvent
+	for _, k := range keys {
+		evs = append(evs, keyEvent{k, 1})
+	}
+	return evs
+}
+
+// release returns key events that release a list of keys in order.
+func release(keys ...keycode.Code) []keyEvent {
+	var evs []keyEvent
+	for _, k := range keys {
+		evs = append(evs, keyEvent{k, 0})
+	}
+	return evs
+}
+
+// tap returns key events that press and release a key.
+func tap(key keycode.Code) []keyEvent {
+	return []keyEvent{{key, 1}, {key, 0}}
+}
+
+func seq(evs ...[]keyEvent) []keyEvent {
+	var all []keyEvent
+	for _, e := range evs {
+		all = append(all, e...)
+	}
+	return all
+}
+
+// run sends key events to a remapper one at a time and returns the output key events.
+func run(s *State, clk *testClock, evs []keyEvent) []keyEvent {
+	var out []keyEvent
+	for _, e := range evs {
+		var events []evdev.InputEvent
+		if e.key == keycode.Code_KEY_RESERVED {
+			clk.t = clk.t.Add(time.Duration(e.value) * time.Millisecond)
+			events = s.sources[0].handleTimeout(clk.now())
+		} else {
+			clk.t = clk.t.Add(time.Millisecond)
+			events = s.handleEvents(s.sources[0], GenKey(e.key, e.value))
+		}
+		for _, ev := range events {
+			if ev.Type == uint16(eventcode.EV_KEY) {
+				out = append(out, keyEvent{keycode.Code(ev.Code), ev.Value})
+			}
+		}
+	}
+	return out
+}
+
+// testOutput is a virtual keyboard that records the key events written to it.
+type testOutput struct {
+	keys []keyEvent
+}
+
+func (o *testOutput) WriteEvents(events []evdev.InputEvent) error {
+	for _, ev := range events {
+		if ev.Type == uint16(eventcode.EV_KEY) {
+			o.keys = append(o.keys, keyEvent{keycode.Code(ev.Code), ev.Value})
+		}
+	}
+	return nil
+}
+
+func (o *testOutput) Close() error {
+	return nil
+}
+
+// testLEDs is an input device that records the states of its LEDs.
+type testLEDs map[keycode.LED]bool
+
+func (l testLEDs) SetLED(led keycode.LED, on bool) error {
+	l[led] = on
+	return nil
+}
+
+func cmpKeys(t *testing.T, name string, got, want []keyEvent) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s: got %v want %v", name, got, want)
+		return
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("%s: got %v want %v", name, got, want)
+			return
+		}
+	}
+}
+
+func TestFnLayers(t *testing.T) {
+	const fn = keycode.Code_KEY_F13
+	tests := []struct {
+		name     string
+		fnEnable bool
+		in       []keyEvent
+		want     []keyEvent
+	}{
+		{
+			name: "unlocked",
+			in:   tap(keycode.Code_KEY_F1),
+			want: tap(keycode.Code_KEY_F1),
+		},
+		{
+			name:     "locked",
+			fnEnable: true,
+			in:       tap(keycode.Code_KEY_F1),
+			want:     tap(keycode.Code_KEY_BACK),
+		},
+		{
+			name: "toggle lock",
+			in:   seq(tap(fn), tap(keycode.Code_KEY_F1)),
+			want: seq(tap(keycode.Code_KEY_FN), tap(keycode.Code_KEY_BACK)),
+		},
+		{
+			name: "fn inverts lock",
+			in:   seq(press(fn), tap(keycode.Code_KEY_F1), release(fn)),
+			want: seq(press(keycode.Code_KEY_FN), tap(keycode.Code_KEY_BACK), release(keycode.Code_KEY_FN)),
+		},
+		{
+			name:     "fn inverts unlock",
+			fnEnable: true,
+			in:       seq(press(fn), tap(keycode.Code_KEY_F1), release(fn), tap(keycode.Code_KEY_F1)),
+			want:     seq(press(keycode.Code_KEY_FN), tap(keycode.Code_KEY_F1), release(keycode.Code_KEY_FN), tap(keycode.Code_KEY_BACK)),
+		},
+		{
+			name: "mod map",
+			in:   seq(press(fn), tap(keycode.Code_KEY_BACKSPACE), release(fn)),
+			want: seq(press(keycode.Code_KEY_FN), tap(keycode.Code_KEY_DELETE), release(keycode.Code_KEY_FN)),
+		},
+		{
+			name:     "shift disables fn lock",
+			fnEnable: true,
+			in:       seq(press(keycode.Code_KEY_LEFTSHIFT), tap(keycode.Code_KEY_F1), release(keycode.Code_KEY_LEFTSHIFT)),
+			want:     seq(press(keycode.Code_KEY_LEFTSHIFT), tap(keycode.Code_KEY_F1), release(keycode.Code_KEY_LEFTSHIFT)),
+		},
+		{
+			name: "third level",
+			in:   seq(press(fn, keycode.Code_KEY_LEFTSHIFT), tap(keycode.Code_KEY_F6), release(keycode.Code_KEY_LEFTSHIFT, fn)),
+			want: seq(
+				press(keycode.Code_KEY_FN, keycode.Code_KEY_LEFTSHIFT),
+				release(keycode.Code_KEY_LEFTSHIFT), tap(keycode.Code_KEY_KBDILLUMDOWN), press(keycode.Code_KEY_LEFTSHIFT),
+				release(keycode.Code_KEY_LEFTSHIFT, keycode.Code_KEY_FN)),
+		},
+	}
+	for _, tc := range tests {
+		cfg := config.DefaultRunConfig()
+		cfg.FnEnabled = tc.fnEnable
+		s, clk := newTestState(cfg)
+		cmpKeys(t, tc.name, run(s, clk, tc.in), tc.want)
+	}
+}
+
+func TestCustomLayers(t *testing.T) {
+	cfg := config.RunConfig{
+		Layers: []config.LayerConfig{
+			{
+				Name:   "nav",
+				Mode:   config.LayerMode_MOMENTARY,
+				Keys:   []keycode.Code{keycode.Code_KEY_CAPSLOCK},
+				KeyMap: config.KeymapOf(map[keycode.Code]keycode.Code{keycode.Code_KEY_H: keycode.Code_KEY_LEFT, keycode.Code_KEY_L: keycode.Code_KEY_RIGHT}),
+			},
+			{
+				Name:        "once",
+				Mode:        config.LayerMode_ONE_SHOT,
+				Transparent: true,
+				Keys:        []keycode.Code{keycode.Code_KEY_RIGHTALT},
+				KeyMap:      config.KeymapOf(map[keycode.Code]keycode.Code{keycode.Code_KEY_H: keycode.Code_KEY_HOME}),
+			},
+		},
+	}
+	s, clk := newTestState(cfg)
+	got := run(s, clk, seq(
+		press(keycode.Code_KEY_CAPSLOCK), tap(keycode.Code_KEY_H), tap(keycode.Code_KEY_J), release(keycode.Code_KEY_CAPSLOCK),
+		tap(keycode.Code_KEY_H),
+		tap(keycode.Code_KEY_RIGHTALT), tap(keycode.Code_KEY_H), tap(keycode.Code_KEY_H),
+		tap(keycode.Code_KEY_RIGHTALT), press(keycode.Code_KEY_LEFTSHIFT), tap(keycode.Code_KEY_H), release(keycode.Code_KEY_LEFTSHIFT),
+	))
+	want := seq(
+		press(keycode.Code_KEY_CAPSLOCK), tap(keycode.Code_KEY_LEFT), tap(keycode.Code_KEY_J), release(keycode.Code_KEY_CAPSLOCK),
+		tap(keycode.Code_KEY_H),
+		tap(keycode.Code_KEY_RIGHTALT), tap(keycode.Code_KEY_HOME), tap(keycode.Code_KEY_H),
+		tap(keycode.Code_KEY_RIGHTALT), press(keycode.Code_KEY_LEFTSHIFT), tap(keycode.Code_KEY_HOME), release(keycode.Code_KEY_LEFTSHIFT),
+	)
+	cmpKeys(t, "custom layers", got, want)
+}
+
+func TestTapHold(t *testing.T) {
+	const (
+		caps = keycode.Code_KEY_CAPSLOCK
+		ctrl = keycode.Code_KEY_LEFTCTRL
+		esc  = keycode.Code_KEY_ESC
+		a    = keycode.Code_KEY_A
+	)
+	capsCtrl := config.TapHoldConfig{Key: caps, Tap: esc, Hold: ctrl}
+	tests := []struct {
+		name string
+		cfg  config.TapHoldConfig
+		in   []keyEvent
+		want []keyEvent
+	}{
+		{
+			name: "tap",
+			cfg:  capsCtrl,
+			in:   tap(caps),
+			want: tap(esc),
+		},
+		{
+			name: "hold",
+			cfg:  capsCtrl,
+			in:   seq(press(caps), wait(300), tap(a), release(caps)),
+			want: seq(press(ctrl), tap(a), release(ctrl)),
+		},
+		{
+			name: "tap before timeout",
+			cfg:  capsCtrl,
+			in:   seq(press(caps), tap(a), release(caps)),
+			want: seq(tap(esc), tap(a)),
+		},
+		{
+			name: "permissive hold",
+			cfg:  config.TapHoldConfig{Key: caps, Tap: esc, Hold: ctrl, PermissiveHold: true},
+			in:   seq(press(caps), tap(a), release(caps)),
+			want: seq(press(ctrl), tap(a), release(ctrl)),
+		},
+		{
+			name: "quick tap",
+			cfg:  config.TapHoldConfig{Key: caps, Tap: esc, Hold: ctrl, QuickTap: 100 * time.Millisecond},
+			in:   seq(tap(caps), press(caps), wait(300), []keyEvent{{caps, 2}}, release(caps)),
+			want: seq(tap(esc), press(esc), []keyEvent{{esc, 2}}, release(esc)),
+		},
+		{
+			name: "hold layer",
+			cfg:  config.TapHoldConfig{Key: keycode.Code_KEY_SPACE, HoldLayer: "nav"},
+			in:   seq(press(keycode.Code_KEY_SPACE), wait(300), tap(keycode.Code_KEY_H), release(keycode.Code_KEY_SPACE), tap(keycode.Code_KEY_SPACE)),
+			want: seq(tap(keycode.Code_KEY_LEFT), tap(keycode.Code_KEY_SPACE)),
+		},
+	}
+	for _, tc := range tests {
+		cfg := config.RunConfig{
+			Layers: []config.LayerConfig{
+				{Name: "nav", KeyMap: config.KeymapOf(map[keycode.Code]keycode.Code{keycode.Code_KEY_H: keycode.Code_KEY_LEFT})},
+			},
+			TapHold: []config.TapHoldConfig{tc.cfg},
+		}
+		s, clk := newTestState(cfg)
+		cmpKeys(t, tc.name, run(s, clk, tc.in), tc.want)
+	}
+
+	// Modifier conditions match the hold output of a held key.
+	ctrlLayer := config.LayerConfig{Name: "ctrl", Mode: config.LayerMode_TOGGLE, Enabled: true, KeyMap: config.KeymapOf(nil)}
+	ctrlLayer.KeyMap.Set(keycode.Code_KEY_BACKSPACE, config.KeyAction{To: keycode.Code_KEY_DELETE, RequireMods: []keycode.Code{ctrl}})
+	cfg := config.RunConfig{Layers: []config.LayerConfig{ctrlLayer}, TapHold: []config.TapHoldConfig{capsCtrl}}
+	s, clk := newTestState(cfg)
+	got := run(s, clk, seq(tap(keycode.Code_KEY_BACKSPACE), press(caps), wait(300), tap(keycode.Code_KEY_BACKSPACE), release(caps)))
+	cmpKeys(t, "hold modifier", got, seq(tap(keycode.Code_KEY_BACKSPACE), press(ctrl), tap(keycode.Code_KEY_DELETE), release(ctrl)))
+
+	// Tapping the FN key while a key is held does not toggle the FN lock.
+	cfg = config.DefaultRunConfig()
+	cfg.TapHold = []config.TapHoldConfig{capsCtrl}
+	s, clk = newTestState(cfg)
+	run(s, clk, seq(press(caps), wait(300), tap(keycode.Code_KEY_F13), release(caps)))
+	if s.profiles[0].fnLocked() {
+		t.Errorf("fn with held key: got FN lock on")
+	}
+
+	// A new configuration releases the output of a held key.
+	cfg = config.RunConfig{TapHold: []config.TapHoldConfig{capsCtrl}}
+	s, clk = newTestState(cfg)
+	out := &testOutput{}
+	s.sources[0].out = out
+	got = run(s, clk, seq(press(caps), wait(300), press(a)))
+	s.SetConfig(s.Config())
+	got = append(got, out.keys...)
+	got = append(got, run(s, clk, release(a, caps))...)
+	cmpKeys(t, "set config", got, seq(press(ctrl, a), release(ctrl, a)))
+}
+
+func TestCombo(t *testing.T) {
+	const (
+		j      = keycode.Code_KEY_J
+		k      = keycode.Code_KEY_K
+		l      = keycode.Code_KEY_L
+		esc    = keycode.Code_KEY_ESC
+		lshift = keycode.Code_KEY_LEFTSHIFT
+		rshift = keycode.Code_KEY_RIGHTSHIFT
+	)
+	cfg := config.RunConfig{
+		Combos: []config.ComboConfig{
+			{Keys: []keycode.Code{j, k}, To: esc, Timeout: 40 * time.Millisecond},
+			{Keys: []keycode.Code{lshift, rshift}, To: keycode.Code_KEY_CAPSLOCK},
+			{Keys: []keycode.Code{j, k, l}, To: keycode.Code_KEY_ENTER},
+		},
+	}
+	tests := []struct {
+		name string
+		in   []keyEvent
+		want []keyEvent
+	}{
+		{
+			name: "combo",
+			in:   seq(press(j, k), wait(50), release(j, k)),
+			want: seq(tap(esc)),
+		},
+		{
+			name: "combo repeat",
+			in:   seq(press(lshift, rshift), []keyEvent{{rshift, 2}}, release(rshift, lshift)),
+			want: seq(press(keycode.Code_KEY_CAPSLOCK), []keyEvent{{keycode.Code_KEY_CAPSLOCK, 2}}, release(keycode.Code_KEY_CAPSLOCK)),
+		},
+		{
+			name: "longer combo",
+			in:   seq(press(j, k, l), release(l, k, j)),
+			want: seq(tap(keycode.Code_KEY_ENTER)),
+		},
+		{
+			name: "timeout",
+			in:   seq(press(j), wait(50), press(k), release(j, k)),
+			want: seq(press(j), press(k), release(j, k)),
+		},
+		{
+			name: "released before combo",
+			in:   seq(tap(j), tap(k)),
+			want: seq(tap(j), tap(k)),
+		},
+		{
+			name: "other key",
+			in:   seq(press(j, keycode.Code_KEY_A), release(j, keycode.Code_KEY_A)),
+			want: seq(press(j, keycode.Code_KEY_A), release(j, keycode.Code_KEY_A)),
+		},
+	}
+	for _, tc := range tests {
+		s, clk := newTestState(cfg)
+		cmpKeys(t, tc.name, run(s, clk, tc.in), tc.want)
+	}
+}
+
+func TestMacro(t *testing.T) {
+	const (
+		fn   = keycode.Code_KEY_F13
+		ctrl = keycode.Code_KEY_LEFTCTRL
+		alt  = keycode.Code_KEY_LEFTALT
+		t_   = keycode.Code_KEY_T
+	)
+	cfg := config.DefaultRunConfig()
+	cfg.ModKeyMap.Set(t_, config.KeyAction{Macro: []config.MacroStepConfig{
+		{Action: config.MacroAction_PRESS, Key: ctrl},
+		{Action: config.MacroAction_PRESS, Key: alt},
+		{Action: config.MacroAction_TAP, Key: t_},
+		{Action: config.MacroAction_RELEASE, Key: alt},
+		{Action: config.MacroAction_RELEASE, Key: ctrl},
+	}})
+	cfg.ModKeyMap.Set(keycode.Code_KEY_Y, config.KeyAction{Macro: []config.MacroStepConfig{
+		{Action: config.MacroAction_PRESS, Key: ctrl},
+		{Action: config.MacroAction_DELAY, Delay: 100 * time.Millisecond},
+		{Action: config.MacroAction_RELEASE, Key: ctrl},
+	}})
+	tests := []struct {
+		name string
+		in   []keyEvent
+		want []keyEvent
+	}{
+		{
+			name: "shortcut",
+			in:   seq(press(fn), tap(t_), release(fn)),
+			want: seq(press(keycode.Code_KEY_FN, ctrl, alt), tap(t_), release(alt, ctrl, keycode.Code_KEY_FN)),
+		},
+		{
+			name: "delay",
+			in:   seq(press(fn), tap(keycode.Code_KEY_Y), wait(50), wait(60), release(fn)),
+			want: seq(press(keycode.Code_KEY_FN, ctrl), release(ctrl, keycode.Code_KEY_FN)),
+		},
+		{
+			name: "interrupt",
+			in:   seq(press(fn), tap(keycode.Code_KEY_Y), tap(keycode.Code_KEY_Y), wait(200), release(fn)),
+			want: seq(press(keycode.Code_KEY_FN, ctrl), release(ctrl, keycode.Code_KEY_FN)),
+		},
+	}
+	for _, tc := range tests {
+		s, clk := newTestState(cfg)
+		cmpKeys(t, tc.name, run(s, clk, tc.in), tc.want)
+	}
+}
+
+func TestModifiers(t *testing.T) {
+	const (
+		fn   = keycode.Code_KEY_F13
+		ctrl = keycode.Code_KEY_LEFTCTRL
+		alt  = keycode.Code_KEY_LEFTALT
+	)
+	cfg := config.DefaultRunConfig()
+	cfg.ModKeyMap.Set(keycode.Code_KEY_H, config.KeyAction{To: keycode.Code_KEY_LEFT, AddMods: []keycode.Code{ctrl}})
+	cfg.ModKeyMap.Set(keycode.Code_KEY_D, config.KeyAction{To: keycode.Code_KEY_DELETE, SuppressMods: []keycode.Code{ctrl}})
+	tests := []struct {
+		name string
+		in   []keyEvent
+		want []keyEvent
+	}{
+		{
+			name: "add",
+			in:   seq(press(fn, keycode.Code_KEY_H), []keyEvent{{keycode.Code_KEY_H, 2}}, release(keycode.Code_KEY_H, fn)),
+			want: seq(press(keycode.Code_KEY_FN, ctrl, keycode.Code_KEY_LEFT), []keyEvent{{keycode.Code_KEY_LEFT, 2}}, release(keycode.Code_KEY_LEFT, ctrl, keycode.Code_KEY_FN)),
+		},
+		{
+			name: "suppress",
+			in:   seq(press(ctrl, fn), tap(keycode.Code_KEY_D), release(fn, ctrl)),
+			want: seq(press(ctrl, keycode.Code_KEY_FN), release(ctrl), tap(keycode.Code_KEY_DELETE), press(ctrl), release(keycode.Code_KEY_FN, ctrl)),
+		},
+		{
+			name: "suppressed modifier released",
+			in:   seq(press(ctrl, fn, keycode.Code_KEY_D), release(ctrl, keycode.Code_KEY_D, fn)),
+			want: seq(press(ctrl, keycode.Code_KEY_FN), release(ctrl), press(keycode.Code_KEY_DELETE), release(ctrl, keycode.Code_KEY_DELETE, keycode.Code_KEY_FN)),
+		},
+		{
+			name: "suppress other side",
+			in:   seq(press(keycode.Code_KEY_RIGHTCTRL, fn), tap(keycode.Code_KEY_D), release(fn, keycode.Code_KEY_RIGHTCTRL)),
+			want: seq(press(keycode.Code_KEY_RIGHTCTRL, keycode.Code_KEY_FN), release(keycode.Code_KEY_RIGHTCTRL), tap(keycode.Code_KEY_DELETE), press(keycode.Code_KEY_RIGHTCTRL), release(keycode.Code_KEY_FN, keycode.Code_KEY_RIGHTCTRL)),
+		},
+		{
+			name: "suppress not held",
+			in:   seq(press(fn, alt), tap(keycode.Code_KEY_D), release(alt, fn)),
+			want: seq(press(keycode.Code_KEY_FN, alt), tap(keycode.Code_KEY_DELETE), release(alt, keycode.Code_KEY_FN)),
+		},
+	}
+	for _, tc := range tests {
+		s, clk := newTestState(cfg)
+		cmpKeys(t, tc.name, run(s, clk, tc.in), tc.want)
+	}
+}
+
+func TestModMorph(t *testing.T) {
+	const (
+		fn     = keycode.Code_KEY_F13
+		ctrl   = keycode.Code_KEY_LEFTCTRL
+		alt    = keycode.Code_KEY_LEFTALT
+		lshift = keycode.Code_KEY_LEFTSHIFT
+		rshift = keycode.Code_KEY_RIGHTSHIFT
+		bs     = keycode.Code_KEY_BACKSPACE
+	)
+	cfg := config.DefaultRunConfig()
+	cfg.KeyMap[keycode.Code_KEY_F1] = append([]config.KeyAction{
+		{To: keycode.Code_KEY_F1, RequireMods: []keycode.Code{ctrl, alt}},
+	}, cfg.KeyMap[keycode.Code_KEY_F1]...)
+	cfg.KeyMap[bs] = []config.KeyAction{
+		{To: keycode.Code_KEY_DELETE, RequireMods: []keycode.Code{lshift}, ForbidMods: []keycode.Code{ctrl}, SuppressMods: []keycode.Code{lshift}},
+	}
+	// Each sequence starts by tapping FN to turn on FN lock.
+	tests := []struct {
+		name string
+		in   []keyEvent
+		want []keyEvent
+	}{
+		{
+			name: "required",
+			in:   seq(press(ctrl, alt), tap(keycode.Code_KEY_F1), release(alt, ctrl)),
+			want: seq(press(ctrl, alt), tap(keycode.Code_KEY_F1), release(alt, ctrl)),
+		},
+		{
+			name: "missing required",
+			in:   seq(press(ctrl), tap(keycode.Code_KEY_F1), release(ctrl)),
+			want: seq(press(ctrl), tap(keycode.Code_KEY_BACK), release(ctrl)),
+		},
+		{
+			name: "required shift",
+			in:   seq(press(lshift), tap(bs), release(lshift)),
+			want: seq(press(lshift), release(lshift), tap(keycode.Code_KEY_DELETE), press(lshift), release(lshift)),
+		},
+		{
+			name: "either side",
+			in:   seq(press(rshift), tap(bs), release(rshift)),
+			want: seq(press(rshift), release(rshift), tap(keycode.Code_KEY_DELETE), press(rshift), release(rshift)),
+		},
+		{
+			name: "shift disables other entries",
+			in:   seq(press(rshift), tap(keycode.Code_KEY_F1), release(rshift)),
+			want: seq(press(rshift), tap(keycode.Code_KEY_F1), release(rshift)),
+		},
+		{
+			name: "forbidden",
+			in:   seq(press(lshift, ctrl), tap(bs), release(ctrl, lshift)),
+			want: seq(press(lshift, ctrl), tap(bs), release(ctrl, lshift)),
+		},
+	}
+	for _, tc := range tests {
+		s, clk := newTestState(cfg)
+		cmpKeys(t, tc.name, run(s, clk, seq(tap(fn), tc.in)), seq(tap(keycode.Code_KEY_FN), tc.want))
+	}
+}
+
+func TestPressReleasePairing(t *testing.T) {
+	const fn = keycode.Code_KEY_F13
+	tests := []struct {
+		name string
+		in   []keyEvent
+		want []keyEvent
+	}{
+		{
+			name: "fn released first",
+			in:   seq(press(fn, keycode.Code_KEY_F1), []keyEvent{{keycode.Code_KEY_F1, 2}}, release(fn), []keyEvent{{keycode.Code_KEY_F1, 2}}, release(keycode.Code_KEY_F1)),
+			want: seq(press(keycode.Code_KEY_FN, keycode.Code_KEY_BACK), []keyEvent{{keycode.Code_KEY_BACK, 2}}, release(keycode.Code_KEY_FN), []keyEvent{{keycode.Code_KEY_BACK, 2}}, release(keycode.Code_KEY_BACK)),
+		},
+		{
+			name: "fn pressed later",
+			in:   seq(press(keycode.Code_KEY_F1, fn), release(keycode.Code_KEY_F1, fn)),
+			want: seq(press(keycode.Code_KEY_F1, keycode.Code_KEY_FN), release(keycode.Code_KEY_F1, keycode.Code_KEY_FN)),
+		},
+	}
+	for _, tc := range tests {
+		s, clk := newTestState(config.DefaultRunConfig())
+		cmpKeys(t, tc.name, run(s, clk, tc.in), tc.want)
+	}
+
+	// A new configuration does not change the release of a pressed key.
+	cfg := config.DefaultRunConfig()
+	cfg.FnEnabled = true
+	s, clk := newTestState(cfg)
+	got := run(s, clk, press(keycode.Code_KEY_F2))
+	s.SetConfig(config.DefaultRunConfig())
+	got = append(got, run(s, clk, release(keycode.Code_KEY_F2))...)
+	cmpKeys(t, "set config", got, tap(keycode.Code_KEY_FORWARD))
+}
+
+func TestSyncKeys(t *testing.T) {
+	const fn = keycode.Code_KEY_F13
+	s, clk := newTestState(config.DefaultRunConfig())
+	got := run(s, clk, press(fn, keycode.Code_KEY_F1))
+
+	// The F1 release and an A press were dropped. Events up to the next SYN_REPORT are discarded.
+	dropped := []evdev.InputEvent{{Type: uint16(eventcode.EV_SYN), Code: uint16(eventcode.SYN_DROPPED)}}
+	dropped = append(dropped, GenKey(keycode.Code_KEY_B, 1)[:2]...)
+	src := s.sources[0]
+	if events := s.handleEvents(src, dropped); len(events) != 0 || !src.dropped {
+		t.Errorf("events after SYN_DROPPED: got %v want none", events)
+	}
+	src.dropped = false
+	var keys keycode.KeyBits
+	keys.Set(fn, true)
+	keys.Set(keycode.Code_KEY_A, true)
+	for _, ev := range s.syncKeys(src, &keys, clk.now()) {
+		if ev.Type == uint16(eventcode.EV_KEY) {
+			got = append(got, keyEvent{keycode.Code(ev.Code), ev.Value})
+		}
+	}
+	got = append(got, run(s, clk, release(keycode.Code_KEY_A, fn))...)
+	want := seq(press(keycode.Code_KEY_FN, keycode.Code_KEY_BACK), release(keycode.Code_KEY_BACK), press(keycode.Code_KEY_A), release(keycode.Code_KEY_A, keycode.Code_KEY_FN))
+	cmpKeys(t, "sync keys", got, want)
+}
+
+func TestProfiles(t *testing.T) {
+	// The first device uses a section with a different FN key, the second device uses the top-level key maps.
+	dev := config.DefaultRunConfig()
+	dev.FnKey = keycode.Code_KEY_F24
+	dev.UseLED = keycode.LED_CNT
+	cfg := config.DefaultRunConfig()
+	cfg.Devices = []config.DeviceConfig{{Match: config.DeviceMatchConfig{Name: "ThinkPad"}, Config: dev}}
+	s, clk := newTestState(cfg)
+	s.sources = append(s.sources, &source{})
+	s.sources[0].setProfile(s.profiles[0])
+	s.sources[1].setProfile(s.profiles[1])
+
+	// Toggling FN lock on one device does not change the other device.
+	got := run(s, clk, seq(tap(keycode.Code_KEY_F24), tap(keycode.Code_KEY_F1)))
+	want := seq(tap(keycode.Code_KEY_FN), tap(keycode.Code_KEY_BACK))
+	cmpKeys(t, "section", got, want)
+
+	var out []keyEvent
+	for _, e := range seq(tap(keycode.Code_KEY_F24), tap(keycode.Code_KEY_F1)) {
+		for _, ev := range s.handleEvents(s.sources[1], GenKey(e.key, e.value)) {
+			if ev.Type == uint16(eventcode.EV_KEY) {
+				out = append(out, keyEvent{keycode.Code(ev.Code), ev.Value})
+			}
+		}
+	}
+	cmpKeys(t, "top-level", out, seq(tap(keycode.Code_KEY_F24), tap(keycode.Code_KEY_F1)))
+}
+
+func TestPerDeviceOutput(t *testing.T) {
+	const (
+		caps  = keycode.Code_KEY_CAPSLOCK
+		ctrl  = keycode.Code_KEY_LEFTCTRL
+		shift = keycode.Code_KEY_LEFTSHIFT
+		a     = keycode.Code_KEY_A
+	)
+	cfg := config.DefaultRunConfig()
+	cfg.TapHold = []config.TapHoldConfig{{Key: caps, Tap: keycode.Code_KEY_ESC, Hold: ctrl}}
+	s, clk := newTestState(cfg)
+	outA, outB := &testOutput{}, &testOutput{}
+	s.sources[0].out = outA
+	s.sources = append(s.sources, &source{out: outB})
+	s.sources[1].setProfile(s.profiles[0])
+	devA, devB := s.sources[0], s.sources[1]
+
+	// Each device has its own virtual keyboard. Key timeouts are sent to the device of the pending key.
+	for _, e := range []struct {
+		src *source
+		keyEvent
+	}{
+		{devA, keyEvent{caps, 1}},
+		{devB, keyEvent{shift, 1}},
+		{devA, keyEvent{shift, 1}},
+		{nil, keyEvent{keycode.Code_KEY_RESERVED, 300}},
+		{devB, keyEvent{a, 1}},
+		{devA, keyEvent{shift, 0}},
+		{devB, keyEvent{a, 0}},
+		{devA, keyEvent{caps, 0}},
+		{devB, keyEvent{shift, 0}},
+	} {
+		if e.src == nil {
+			clk.t = clk.t.Add(time.Duration(e.value) * time.Millisecond)
+			for _, src := range s.sources {
+				s.writeEvents(src, src.handleTimeout(clk.now()))
+			}
+			continue
+		}
+		clk.t = clk.t.Add(time.Millisecond)
+		s.writeEvents(e.src, s.handleEvents(e.src, GenKey(e.key, e.value)))
+	}
+	cmpKeys(t, "device A", outA.keys, seq(press(ctrl, shift), release(shift, ctrl)))
+	cmpKeys(t, "device B", outB.keys, seq(press(shift), tap(a), release(shift)))
+}
+
+func TestLEDBindings(t *testing.T) {
+	cfg := config.DefaultRunConfig()
+	cfg.LEDs = []config.LEDBindingConfig{
+		{LED: keycode.LED_CAPSL, State: config.LEDState_LAYER_ACTIVE, Layer: config.FnLayer},
+		{LED: keycode.LED_SCROLLL, State: config.LEDState_CONFIG_ERROR, Pattern: config.LEDPattern_BLINK},
+	}
+	cfg.UseLED = keycode.LED_CNT
+	// Add the source after SetConfig, which sets the LEDs of the sources.
+	clk := &testClock{t: time.Unix(1000, 0)}
+	s := &State{now: clk.now}
+	s.SetConfig(cfg)
+	p := s.profiles[0]
+	s.sources = []*source{{}}
+	s.sources[0].setProfile(p)
+	if !p.isBound(keycode.LED_CAPSL) || p.isBound(keycode.LED_NUML) {
+		t.Errorf("isBound: bad bound LEDs")
+	}
+
+	fnLock := config.LEDBindingConfig{State: config.LEDState_FN_LOCK}
+	tests := []struct {
+		name string
+		in   []keyEvent
+		b    config.LEDBindingConfig
+		want bool
+	}{
+		{"fn lock off", nil, fnLock, false},
+		{"fn lock on", tap(keycode.Code_KEY_F13), fnLock, true},
+		{"fn held", press(keycode.Code_KEY_F13), cfg.LEDs[0], true},
+		{"fn released", release(keycode.Code_KEY_F13), cfg.LEDs[0], false},
+		{"config ok", nil, cfg.LEDs[1], false},
+	}
+	for _, tc := range tests {
+		run(s, clk, tc.in)
+		if got := s.isActive(p, tc.b); got != tc.want {
+			t.Errorf("%s: got %v want %v", tc.name, got, tc.want)
+		}
+	}
+	s.cfgErr = true
+	if !s.isActive(p, cfg.LEDs[1]) {
+		t.Errorf("config error: got false want true")
+	}
+}
+
+func TestMirrorLEDs(t *testing.T) {
+	cfg := config.DefaultRunConfig()
+	cfg.UseLED = keycode.LED_CAPSL
+	clk := &testClock{t: time.Unix(1000, 0)}
+	leds := testLEDs{}
+	s := &State{now: clk.now, sources: []*source{{ledOut: leds}}, leds: map[keycode.LED]bool{}}
+	s.SetConfig(cfg)
+	ledEvent := func(led keycode.LED, on bool) evdev.InputEvent {
+		ev := evdev.InputEvent{Type: uint16(eventcode.EV_LED), Code: uint16(led)}
+		if on {
+			ev.Value = 1
+		}
+		return ev
+	}
+
+	// The FN lock LED is reserved. The other LEDs mirror the desktop.
+	s.mirrorLEDs([]evdev.InputEvent{ledEvent(keycode.LED_NUML, true), ledEvent(keycode.LED_CAPSL, true)})
+	if !leds[keycode.LED_NUML] || leds[keycode.LED_CAPSL] {
+		t.Errorf("mirror: got %v want NUML on and CAPSL off", leds)
+	}
+	run(s, clk, tap(keycode.Code_KEY_F13))
+	s.updateLEDs()
+	if !leds[keycode.LED_CAPSL] {
+		t.Errorf("fn lock on: got %v want CAPSL on", leds)
+	}
+	s.mirrorLEDs([]evdev.InputEvent{ledEvent(keycode.LED_NUML, false), ledEvent(keycode.LED_CAPSL, false)})
+	if leds[keycode.LED_NUML] || !leds[keycode.LED_CAPSL] {
+		t.Errorf("mirror with fn lock on: got %v want NUML off and CAPSL on", leds)
+	}
+
+	// A reload that removes the binding of a lit LED restores the LED.
+	cfg.LEDs = []config.LEDBindingConfig{{LED: keycode.LED_SCROLLL, State: config.LEDState_CONFIG_ERROR}}
+	s.SetConfig(cfg)
+	s.SetConfigError(true)
+	if !leds[keycode.LED_SCROLLL] {
+		t.Errorf("config error: got %v want SCROLLL on", leds)
+	}
+	cfg.LEDs, cfg.UseLED = nil, keycode.LED_CNT
+	s.SetConfig(cfg)
+	if leds[keycode.LED_SCROLLL] || leds[keycode.LED_CAPSL] {
+		t.Errorf("unbound: got %v want SCROLLL and CAPSL off", leds)
+	}
+}
+
+func TestPause(t *testing.T) {
+	cfg := config.DefaultRunConfig()
+	cfg.FnEnabled = true
+	s, clk := newTestState(cfg)
+	s.Pause()
+	got := run(s, clk, tap(keycode.Code_KEY_F1))
+	cmpKeys(t, "paused", got, tap(keycode.Code_KEY_F1))
+	s.Resume()
+	got = run(s, clk, tap(keycode.Code_KEY_F1))
+	cmpKeys(t, "resumed", got, tap(keycode.Code_KEY_BACK))
+
+	// The key states resynchronized while paused are sent without remapping.
+	src := &source{}
+	src.inKeys.Set(keycode.Code_KEY_F1, true)
+	src.inKeys.Set(keycode.Code_KEY_F13, true)
+	var keys keycode.KeyBits
+	keys.Set(keycode.Code_KEY_F13, true)
+	keys.Set(keycode.Code_KEY_F2, true)
+	got = nil
+	for _, ev := range passKeys(src, &keys) {
+		if ev.Type == uint16(eventcode.EV_KEY) {
+			got = append(got, keyEvent{keycode.Code(ev.Code), ev.Value})
+		}
+	}
+	cmpKeys(t, "resync paused", got, seq(release(keycode.Code_KEY_F1), press(keycode.Code_KEY_F2)))
+	if src.inKeys != keys {
+		t.Errorf("resync paused: inKeys not updated")
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	s := &State{now: time.Now}
+	s.SetConfig(config.DefaultRunConfig())
+	c := s.Subscribe()
+	s.SetFnLock(true)
+	s.checkChanges()
+	s.Pause()
+	s.Unsubscribe(c)
+
+	var got []EventType
+	for ev := range c {
+		got = append(got, ev.Type)
+	}
+	want := []EventType{EventStatus, EventFnLock, EventPaused}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events: got %v want %v", got, want)
+	}
+
+	// Holding keys publishes no events and the events have no pressed keys or momentary layers.
+	s, clk := newTestState(config.DefaultRunConfig())
+	s.sources[0].out = &testOutput{}
+	c = s.Subscribe()
+	run(s, clk, press(keycode.Code_KEY_LEFTSHIFT, keycode.Code_KEY_F13))
+	s.checkChanges()
+	s.Pause()
+	s.Unsubscribe(c)
+	got = nil
+	var last Event
+	for ev := range c {
+		got, last = append(got, ev.Type), ev
+	}
+	want = []EventType{EventStatus, EventPaused}
+	if !reflect.DeepEqual(got, want) || len(last.Status.PressedKeys) != 0 || len(last.Status.ActiveLayers) != 0 {
+		t.Errorf("held keys: got %v %+v want %v without pressed keys and layers", got, last.Status, want)
+	}
+
+	// A subscriber that is behind misses its oldest events.
+	c = s.Subscribe()
+	for i := 0; i < 2*subscriberBuffer; i++ {
+		s.SetFnLock(i%2 == 0)
+		s.checkChanges()
+	}
+	s.Resume()
+	s.Unsubscribe(c)
+	var evs []Event
+	for ev := range c {
+		evs = append(evs, ev)
+	}
+	if len(evs) != subscriberBuffer || evs[len(evs)-1].Type != EventPaused || evs[len(evs)-1].Status.Paused {
+		t.Errorf("slow subscriber: got %+v want %d events ending with the resume", evs, subscriberBuffer)
+	}
+}
@@ -0,0 +1,226 @@
+package remap
+
+import (
+	"context"
+
+	"github.com/erdichen/chromekey/evdev"
+	"github.com/erdichen/chromekey/evdev/keycode"
+	"github.com/erdichen/chromekey/log"
+	"github.com/erdichen/chromekey/uinput"
+)
+
+// source is an input device attached to a remapper.
+type source struct {
+	in      *evdev.Device
+	info    *evdev.Info          // Identity of the input device queried when it was attached.
+	out     keyWriter            // The shared virtual keyboard or the virtual keyboard of this device.
+	ledOut  ledSetter            // The input device, whose LEDs mirror the desktop and the remapper states.
+	profile *profile             // Key maps of this device.
+	inKeys  keycode.KeyBits      // Pressed keys of the input device.
+	leds    map[keycode.LED]bool // States of the LEDs bound to remapper states, nil if unknown.
+	dropped bool                 // Discarding events after SYN_DROPPED.
+
+	// Key states of this device. The key events of timeouts are sent to the virtual keyboard of this device.
+	tapHold tapHoldState
+	combo   comboState
+	macro   macroPlayer
+	pressed map[keycode.Code]pressedKey // Outputs of the pressed keys.
+	lastKey keycode.Code
+	keys    keycode.KeyBits // Pressed keys after combo and tap-hold processing.
+}
+
+// keyWriter is a virtual keyboard that receives the remapped key events.
+type keyWriter interface {
+	WriteEvents(events []evdev.InputEvent) error
+	Close() error
+}
+
+// ledSetter sets the LEDs of an input device.
+type ledSetter interface {
+	SetLED(led keycode.LED, on bool) error
+}
+
+// sourceEvents is a batch of input events read from a source. Nil events means the source has stopped.
+type sourceEvents struct {
+	src    *source
+	events []evdev.InputEvent
+}
+
+// attach grabs an input device and starts forwarding its events to the remapper.
+func (s *State) attach(ctx context.Context, in *evdev.Device, waitRelease bool) error {
+	ok := false
+
+	defer func() {
+		if !ok {
+			in.Close()
+		}
+	}()
+
+	// Grabbing an input device will cause any pressed key to stuck in the pressed state.
+	if waitRelease {
+		if err := waitForAllKeysReleased(in); err != nil {
+			return err
+		}
+	}
+
+	if s.opts.Grab {
+		if err := in.Grab(); err != nil {
+			return err
+		}
+	}
+
+	// Snapshot the pressed keys after grabbing so that their releases are sent to the remapper.
+	var keys keycode.KeyBits
+	if !waitRelease {
+		if err := in.GetKeyStates(keys[:]); err != nil {
+			return err
+		}
+	}
+
+	info, err := in.Info()
+	if err != nil {
+		info = &evdev.Info{Path: in.Path()}
+	}
+	src := &source{in: in, info: info, ledOut: in}
+	src.setProfile(s.profileOf(in))
+	if s.out != nil {
+		src.out = s.out
+	} else {
+		// Create an virtual device that replicates the capabilities and keys of the give input device.
+		b, err := uinput.NewBuilderFromDevice(in)
+		if err != nil {
+			return err
+		}
+		if err := s.setIdentity(b.AddRemapCapabilities(), in); err != nil {
+			return err
+		}
+		out, err := b.Create(s.opts.OutputDev)
+		if err != nil {
+			return err
+		}
+		src.out = out
+		s.startLEDLoop(ctx, out)
+	}
+
+	ok = true
+	s.sources = append(s.sources, src)
+	s.setLEDs(src)
+	if s.paused {
+		// Press the held keys without remapping like Pause.
+		s.writeEvents(src, genKeys(&keys, 1))
+		src.inKeys = keys
+	} else {
+		s.writeEvents(src, s.syncKeys(src, &keys, s.now()))
+	}
+	s.startReadLoop(ctx, src)
+	return nil
+}
+
+// setIdentity sets the name, phys and IDs of a virtual keyboard from the configuration and its input device.
+func (s *State) setIdentity(b *uinput.Builder, in *evdev.Device) error {
+	var info *evdev.Info
+	if in != nil && s.cfg.VirtualDevice.CopySource {
+		var err error
+		if info, err = in.Info(); err != nil {
+			return err
+		}
+	}
+	name, phys, id, err := s.cfg.VirtualDevice.Identity(info)
+	if err != nil {
+		return err
+	}
+	b.SetName(name).SetPhys(phys).SetID(id)
+	return nil
+}
+
+// hotplug attaches a new input device if it is selected by the device matcher.
+// It does not wait for the pressed keys to be released to not block the remapper.
+func (s *State) hotplug(ctx context.Context, path string) {
+	for _, src := range s.sources {
+		if src.in.Path() == path {
+			return
+		}
+	}
+	in, err := evdev.OpenDevice(path)
+	if err != nil {
+		// The device may not be accessible until udev has set it up.
+		if verbosity > 1 {
+			log.Infof("failed to open new input device %v: %v", path, err)
+		}
+		return
+	}
+	if !s.opts.Match(in) {
+		in.Close()
+		return
+	}
+	if err := s.attach(ctx, in, false); err != nil {
+		log.Errorf("failed to attach input device %v: %v", path, err)
+		return
+	}
+	info := s.sources[len(s.sources)-1].info
+	if verbosity > 0 {
+		log.Infof("attached input device %v", info)
+	}
+	s.publish(EventAttached, info)
+}
+
+// detach releases the keys held by a source and closes its devices.
+func (s *State) detach(src *source) error {
+	for i, v := range s.sources {
+		if v == src {
+			s.sources = append(s.sources[:i], s.sources[i+1:]...)
+			break
+		}
+	}
+	s.publish(EventDetached, src.info)
+
+	var errs []error
+	if s.paused {
+		// Release the held keys without remapping like Resume.
+		s.writeEvents(src, genKeys(&src.inKeys, 0))
+	} else {
+		s.writeEvents(src, s.syncKeys(src, &keycode.KeyBits{}, s.now()))
+	}
+	if src.out != s.out {
+		errs = append(errs, src.out.Close())
+	}
+	errs = append(errs, src.in.Ungrab(), src.in.Close())
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setProfile switches a source to the key maps of a profile.
+// The pressed keys are taken over so that they are released with their old outputs.
+func (src *source) setProfile(p *profile) {
+	src.profile = p
+	src.tapHold = newTapHoldState(p.cfg.TapHold)
+	src.combo = newComboState(p.cfg.Combos)
+	if src.pressed == nil {
+		src.pressed = map[keycode.Code]pressedKey{}
+	}
+}
+
+// startReadLoop loops reading input events from a source and sends them to the remapper.
+func (s *State) startReadLoop(ctx context.Context, src *source) {
+	go func() {
+		for {
+			events, err := src.in.ReadEvents(ctx)
+			if err != nil {
+				log.Errorf("failed to read from evdev input: %v", err)
+				events = nil
+			}
+			select {
+			case s.evC <- sourceEvents{src: src, events: events}:
+			case <-ctx.Done():
+				return
+			}
+			if events == nil {
+				return
+			}
+		}
+	}()
+}
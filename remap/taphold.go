@@ -0,0 +1,184 @@
+package remap
+
+import (
+	"time"
+
+	"github.com/erdichen/chromekey/evdev"
+	"github.com/erdichen/chromekey/evdev/keycode"
+	"github.com/erdichen/chromekey/log"
+	"github.com/erdichen/chromekey/remap/config"
+)
+
+// tapHold is the runtime state of a dual-role key.
+type tapHold struct {
+	cfg     config.TapHoldConfig
+	held    bool      // Resolved as held.
+	tapping bool      // Resolved as a quick tap with the tap key held down.
+	pressed time.Time // Time of the last key press.
+	tapped  time.Time // Time of the last tap.
+}
+
+// tapHoldState resolves dual-role keys. Key events are buffered while a key is undecided.
+type tapHoldState struct {
+	keys    map[keycode.Code]*tapHold
+	pending *tapHold
+	buffer  []evdev.InputEvent
+	down    keycode.KeyBits // Keys pressed while the pending key is undecided.
+	holds   keycode.KeyBits // Hold outputs of the keys resolved as held, which count as held modifiers.
+}
+
+func newTapHoldState(cfgs []config.TapHoldConfig) tapHoldState {
+	ts := tapHoldState{keys: map[keycode.Code]*tapHold{}}
+	for _, c := range cfgs {
+		ts.keys[c.Key] = &tapHold{cfg: c}
+	}
+	return ts
+}
+
+// deadline returns when the pending key resolves as held.
+func (ts *tapHoldState) deadline() (time.Time, bool) {
+	if ts.pending == nil {
+		return time.Time{}, false
+	}
+	return ts.pending.pressed.Add(ts.pending.cfg.HoldTimeout()), true
+}
+
+// isHeld returns true if any dual-role key is held down after it has been resolved.
+func (ts *tapHoldState) isHeld() bool {
+	for _, th := range ts.keys {
+		if th.held || th.tapping {
+			return true
+		}
+	}
+	return false
+}
+
+// modKeys returns the pressed keys together with the hold outputs of the held dual-role keys.
+func (src *source) modKeys() *keycode.KeyBits {
+	keys := src.keys
+	for i := range keys {
+		keys[i] |= src.tapHold.holds[i]
+	}
+	return &keys
+}
+
+// processTapHold resolves dual-role keys before a key event is mapped.
+func (src *source) processTapHold(ev evdev.InputEvent, now time.Time) []evdev.InputEvent {
+	key := keycode.Code(ev.Code)
+	if pending := src.tapHold.pending; pending != nil {
+		switch {
+		case key == pending.cfg.Key && ev.Value == 0:
+			return src.resolveTapHold(false, now)
+		case key == pending.cfg.Key:
+			// Drop auto-repeats while undecided.
+			return nil
+		}
+		src.tapHold.buffer = append(src.tapHold.buffer, ev)
+		if ev.Value == 1 {
+			src.tapHold.down.Set(key, true)
+		} else if ev.Value == 0 && pending.cfg.PermissiveHold && src.tapHold.down.Get(key) {
+			return src.resolveTapHold(true, now)
+		}
+		return nil
+	}
+
+	th, ok := src.tapHold.keys[key]
+	if !ok {
+		return src.mapKey(ev, now)
+	}
+	src.lastKey = key
+	switch ev.Value {
+	case 1:
+		if th.cfg.QuickTap > 0 && now.Sub(th.tapped) < th.cfg.QuickTap {
+			th.tapping = true
+			return GenKey(th.cfg.TapKey(), 1)
+		}
+		th.pressed = now
+		src.tapHold.pending = th
+		src.tapHold.down = keycode.KeyBits{}
+	case 2:
+		if th.tapping {
+			return GenKey(th.cfg.TapKey(), 2)
+		}
+		if th.held && th.cfg.Hold != keycode.Code_KEY_RESERVED {
+			return GenKey(th.cfg.Hold, 2)
+		}
+	case 0:
+		if th.tapping {
+			th.tapping = false
+			th.tapped = now
+			return GenKey(th.cfg.TapKey(), 0)
+		}
+		if th.held {
+			th.held = false
+			if th.cfg.HoldLayer != "" {
+				src.profile.layers.hold(th.cfg.HoldLayer, false)
+			}
+			if th.cfg.Hold != keycode.Code_KEY_RESERVED {
+				src.tapHold.holds.Set(th.cfg.Hold, false)
+				return GenKey(th.cfg.Hold, 0)
+			}
+		}
+	}
+	return nil
+}
+
+// releaseTapHold resolves the pending key as held and releases the outputs of the held and quick-tapped keys.
+func (src *source) releaseTapHold(now time.Time) []evdev.InputEvent {
+	var events []evdev.InputEvent
+	for src.tapHold.pending != nil {
+		events = append(events, src.resolveTapHold(true, now)...)
+	}
+	for _, th := range src.tapHold.keys {
+		if th.tapping {
+			th.tapping = false
+			events = append(events, GenKey(th.cfg.TapKey(), 0)...)
+		}
+		if th.held {
+			th.held = false
+			if th.cfg.HoldLayer != "" {
+				src.profile.layers.hold(th.cfg.HoldLayer, false)
+			}
+			if th.cfg.Hold != keycode.Code_KEY_RESERVED {
+				src.tapHold.holds.Set(th.cfg.Hold, false)
+				events = append(events, GenKey(th.cfg.Hold, 0)...)
+			}
+		}
+	}
+	return events
+}
+
+// resolveTapHold decides whether the pending key is tapped or held and then replays the buffered key events.
+func (src *source) resolveTapHold(hold bool, now time.Time) []evdev.InputEvent {
+	th := src.tapHold.pending
+	src.tapHold.pending = nil
+
+	var events []evdev.InputEvent
+	if hold {
+		if verbosity > 0 {
+			log.Infof("hold %v", th.cfg.Key)
+		}
+		th.held = true
+		if th.cfg.HoldLayer != "" {
+			src.profile.layers.hold(th.cfg.HoldLayer, true)
+		}
+		if th.cfg.Hold != keycode.Code_KEY_RESERVED {
+			src.tapHold.holds.Set(th.cfg.Hold, true)
+			events = append(events, GenKey(th.cfg.Hold, 1)...)
+		}
+	} else {
+		if verbosity > 0 {
+			log.Infof("tap %v", th.cfg.Key)
+		}
+		th.tapped = now
+		events = append(events, GenKey(th.cfg.TapKey(), 1)...)
+		events = append(events, GenKey(th.cfg.TapKey(), 0)...)
+	}
+
+	buffer := src.tapHold.buffer
+	src.tapHold.buffer = nil
+	for _, ev := range buffer {
+		events = append(events, src.processTapHold(ev, now)...)
+	}
+	return events
+}
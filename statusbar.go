@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/erdichen/chromekey/control"
+	"github.com/erdichen/chromekey/remap"
+)
+
+// runStatus follows the state changes of a running remapper and prints a line for a status bar after each change.
+// It reconnects when the remapper restarts.
+func runStatus(socket string, args []string) error {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	format := fs.String("format", "plain", "Output format: plain, waybar or i3bar")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if socket == "" {
+		return fmt.Errorf("the control socket is disabled, set -control_socket")
+	}
+
+	var line func(st *remap.Status) string
+	sep := ""
+	switch *format {
+	case "plain":
+		line = plainStatus
+	case "waybar":
+		line = waybarStatus
+	case "i3bar":
+		// The i3bar protocol starts with a header and an endless array of status lines.
+		fmt.Println(`{"version":1}`)
+		fmt.Println("[")
+		line = i3barStatus
+	default:
+		return fmt.Errorf("invalid status format: %q", *format)
+	}
+
+	last := ""
+	print := func(st *remap.Status) {
+		// Skip the changes that the status line does not show, e.g. the pressed keys.
+		if s := line(st); s != last {
+			fmt.Println(sep + s)
+			last = s
+			if *format == "i3bar" {
+				sep = ","
+			}
+		}
+	}
+	for {
+		sub, err := control.Subscribe(socket)
+		if err == nil {
+			for {
+				ev, err := sub.Next()
+				if err != nil {
+					break
+				}
+				print(&ev.Status)
+			}
+			sub.Close()
+		}
+		print(nil)
+		time.Sleep(time.Second)
+	}
+}
+
+// statusText returns a short description of the state of a remapper, or of a remapper that is not running if st is nil.
+func statusText(st *remap.Status) string {
+	if st == nil {
+		return "chromekey not running"
+	}
+	text := "FN lock off"
+	if st.FnLock {
+		text = "FN lock on"
+	}
+	if st.Paused {
+		text += " (paused)"
+	}
+	if st.ConfigError {
+		text += " (config error)"
+	}
+	return text
+}
+
+// plainStatus returns the state and the active layers of a remapper as plain text, e.g. for polybar.
+func plainStatus(st *remap.Status) string {
+	text := statusText(st)
+	if st != nil && len(st.ActiveLayers) > 0 {
+		text += " [" + strings.Join(st.ActiveLayers, " ") + "]"
+	}
+	return text
+}
+
+// waybarStatus returns the state of a remapper as the JSON output of a waybar custom module with "return-type": "json".
+func waybarStatus(st *remap.Status) string {
+	class := "stopped"
+	text := ""
+	tooltip := []string{statusText(st)}
+	if st != nil {
+		class = "unlocked"
+		if st.FnLock {
+			class, text = "locked", "FN"
+		}
+		if st.Paused {
+			class = "paused"
+		}
+		if len(st.ActiveLayers) > 0 {
+			tooltip = append(tooltip, "Layers: "+strings.Join(st.ActiveLayers, " "))
+		}
+		for _, d := range st.Devices {
+			if d.Info != nil {
+				tooltip = append(tooltip, "Keyboard: "+d.Info.Name)
+			}
+		}
+	}
+	b, _ := json.Marshal(struct {
+		Text    string `json:"text"`
+		Alt     string `json:"alt"`
+		Class   string `json:"class"`
+		Tooltip string `json:"tooltip"`
+	}{text, class, class, strings.Join(tooltip, "\n")})
+	return string(b)
+}
+
+// i3barStatus returns the state of a remapper as an i3bar protocol status line with one block.
+func i3barStatus(st *remap.Status) string {
+	b, _ := json.Marshal([]struct {
+		Name     string `json:"name"`
+		FullText string `json:"full_text"`
+		Urgent   bool   `json:"urgent,omitempty"`
+	}{{"chromekey", statusText(st), st != nil && st.ConfigError}})
+	return string(b)
+}
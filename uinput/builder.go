@@ -0,0 +1,317 @@
+package uinput
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"github.com/erdichen/chromekey/evdev"
+	"github.com/erdichen/chromekey/evdev/eventcode"
+	"github.com/erdichen/chromekey/evdev/keycode"
+	"golang.org/x/sys/unix"
+)
+
+// Builder declares the identity and capabilities of a virtual input device.
+// Adding an event code also adds its event type.
+type Builder struct {
+	name  string
+	phys  string
+	id    evdev.InputID
+	types [eventcode.EV_CNT]bool
+	keys  keycode.KeyBits
+	rel   [keycode.Rel_REL_CNT]bool
+	abs   map[keycode.Abs]evdev.AbsInfo
+	sw    [keycode.Switch_SW_CNT]bool
+	msc   [eventcode.MSC_CNT]bool
+	led   [keycode.LED_CNT]bool
+	snd   [keycode.Sound_SND_CNT]bool
+	props [keycode.Prop_INPUT_PROP_CNT]bool
+}
+
+// NewBuilder returns a builder of a device with the default name and IDs of the remapper's virtual keyboard.
+func NewBuilder() *Builder {
+	return &Builder{
+		name: evdev.RemapDeviceName,
+		id:   evdev.InputID{BusType: 3, Vendor: 1, Product: 1, Version: 9999},
+		abs:  map[keycode.Abs]evdev.AbsInfo{},
+	}
+}
+
+// NewBuilderFromDevice returns a builder of a device with all the capabilities of an input device.
+// Autorepeat and force feedback are left out; the repeats of the input device are forwarded instead.
+func NewBuilderFromDevice(in *evdev.Device) (*Builder, error) {
+	c, err := in.GetCapabilities()
+	if err != nil {
+		return nil, err
+	}
+	b := NewBuilder()
+	b.AddCapabilities(c)
+	return b, nil
+}
+
+// SetName sets the device name.
+func (b *Builder) SetName(name string) *Builder {
+	b.name = name
+	return b
+}
+
+// SetPhys sets the physical path of the device.
+func (b *Builder) SetPhys(phys string) *Builder {
+	b.phys = phys
+	return b
+}
+
+// SetID sets the bus type, vendor, product and version of the device.
+func (b *Builder) SetID(id evdev.InputID) *Builder {
+	b.id = id
+	return b
+}
+
+// AddTypes adds event types without any event code, e.g. EV_REP.
+func (b *Builder) AddTypes(types ...eventcode.EventType) *Builder {
+	for _, t := range types {
+		b.types[t] = true
+	}
+	return b
+}
+
+// AddKeys adds keys and buttons.
+func (b *Builder) AddKeys(keys ...keycode.Code) *Builder {
+	b.types[eventcode.EV_KEY] = true
+	for _, k := range keys {
+		b.keys.Set(k, true)
+	}
+	return b
+}
+
+// AddKeyBits adds the keys and buttons in a key set.
+func (b *Builder) AddKeyBits(bits *keycode.KeyBits) *Builder {
+	b.types[eventcode.EV_KEY] = true
+	for i := range bits {
+		b.keys[i] |= bits[i]
+	}
+	return b
+}
+
+// AddRel adds relative axes.
+func (b *Builder) AddRel(axes ...keycode.Rel) *Builder {
+	b.types[eventcode.EV_REL] = true
+	for _, a := range axes {
+		b.rel[a] = true
+	}
+	return b
+}
+
+// AddAbs adds an absolute axis with its range.
+func (b *Builder) AddAbs(axis keycode.Abs, info evdev.AbsInfo) *Builder {
+	b.types[eventcode.EV_ABS] = true
+	b.abs[axis] = info
+	return b
+}
+
+// AddSwitches adds switches.
+func (b *Builder) AddSwitches(sw ...keycode.Switch) *Builder {
+	b.types[eventcode.EV_SW] = true
+	for _, v := range sw {
+		b.sw[v] = true
+	}
+	return b
+}
+
+// AddMisc adds misc event codes.
+func (b *Builder) AddMisc(codes ...eventcode.MiscEvent) *Builder {
+	b.types[eventcode.EV_MSC] = true
+	for _, c := range codes {
+		b.msc[c] = true
+	}
+	return b
+}
+
+// AddLEDs adds LEDs.
+func (b *Builder) AddLEDs(leds ...keycode.LED) *Builder {
+	b.types[eventcode.EV_LED] = true
+	for _, l := range leds {
+		b.led[l] = true
+	}
+	return b
+}
+
+// AddSounds adds sounds.
+func (b *Builder) AddSounds(snd ...keycode.Sound) *Builder {
+	b.types[eventcode.EV_SND] = true
+	for _, v := range snd {
+		b.snd[v] = true
+	}
+	return b
+}
+
+// AddProps adds input properties.
+func (b *Builder) AddProps(props ...keycode.Prop) *Builder {
+	for _, p := range props {
+		b.props[p] = true
+	}
+	return b
+}
+
+// AddCapabilities adds the capabilities of an input device, except autorepeat and force feedback.
+// An absolute axis that has already been added keeps its range.
+func (b *Builder) AddCapabilities(c *evdev.Capabilities) *Builder {
+	for _, t := range c.Types {
+		switch t {
+		case eventcode.EV_REP, eventcode.EV_FF, eventcode.EV_FF_STATUS, eventcode.EV_PWR:
+		default:
+			b.types[t] = true
+		}
+	}
+	if c.HasType(eventcode.EV_KEY) {
+		b.AddKeyBits(c.Keys)
+	}
+	for _, a := range c.Rel.Codes() {
+		b.rel[a] = true
+	}
+	for _, a := range c.Abs.Codes() {
+		if _, ok := b.abs[a]; !ok {
+			b.abs[a] = c.AbsInfo[a]
+		}
+	}
+	for _, v := range c.Switch.Codes() {
+		b.sw[v] = true
+	}
+	for _, v := range c.Misc.Codes() {
+		b.msc[v] = true
+	}
+	for _, v := range c.LED.Codes() {
+		b.led[v] = true
+	}
+	for _, v := range c.Sound.Codes() {
+		b.snd[v] = true
+	}
+	return b.AddProps(c.Props...)
+}
+
+// Create creates the virtual device. Its identity is recorded so that device matchers skip it.
+func (b *Builder) Create(device string) (*Device, error) {
+	f, err := os.OpenFile(device, os.O_RDWR|unix.O_NONBLOCK, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := control(f, b.setup); err != nil {
+		f.Close()
+		return nil, err
+	}
+	info := evdev.Info{Name: b.deviceName(), Phys: b.phys, ID: b.id}
+	evdev.AddRemapDevice(info.Name, info.Phys, info.ID)
+	return &Device{f: f, info: info}, nil
+}
+
+// deviceName returns the device name truncated to the maximum length of uinput.
+func (b *Builder) deviceName() string {
+	if len(b.name) > MaxNameSize-1 {
+		return b.name[:MaxNameSize-1]
+	}
+	return b.name
+}
+
+// setup declares the capabilities and identity of a uinput device and then creates it.
+func (b *Builder) setup(fd int) error {
+	types := b.types
+	types[eventcode.EV_SYN] = true
+	for t, ok := range types {
+		if ok {
+			if err := unix.IoctlSetInt(fd, UI_SET_EVBIT, t); err != nil {
+				return err
+			}
+		}
+	}
+
+	bits := []struct {
+		req uint
+		set []bool
+	}{
+		{UI_SET_RELBIT, b.rel[:]},
+		{UI_SET_SWBIT, b.sw[:]},
+		{UI_SET_MSCBIT, b.msc[:]},
+		{UI_SET_LEDBIT, b.led[:]},
+		{UI_SET_SNDBIT, b.snd[:]},
+		{UI_SET_PROPBIT, b.props[:]},
+	}
+	for _, v := range bits {
+		for code, ok := range v.set {
+			if ok {
+				if err := unix.IoctlSetInt(fd, v.req, code); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for k := keycode.Code(0); k < keycode.Code_KEY_CNT; k++ {
+		if b.keys.Get(k) {
+			if err := unix.IoctlSetInt(fd, UI_SET_KEYBIT, int(k)); err != nil {
+				return err
+			}
+		}
+	}
+	for axis := range b.abs {
+		if err := unix.IoctlSetInt(fd, UI_SET_ABSBIT, int(axis)); err != nil {
+			return err
+		}
+	}
+
+	if b.phys != "" {
+		phys, err := unix.BytePtrFromString(b.phys)
+		if err != nil {
+			return err
+		}
+		if _, _, e1 := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(UI_SET_PHYS), uintptr(unsafe.Pointer(phys))); e1 != 0 {
+			return e1
+		}
+	}
+
+	setup := Setup{ID: b.id}
+	copy(setup.Name[:], b.deviceName())
+	_, _, e1 := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(UI_DEV_SETUP), uintptr(unsafe.Pointer(&setup)))
+	switch e1 {
+	case 0:
+		for axis, info := range b.abs {
+			abs := AbsSetup{Code: uint16(axis), AbsInfo: info}
+			if _, _, e1 := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(UI_ABS_SETUP), uintptr(unsafe.Pointer(&abs))); e1 != 0 {
+				return e1
+			}
+		}
+	case unix.EINVAL, unix.ENOTTY:
+		// Kernels before 4.5 don't have UI_DEV_SETUP.
+		if err := b.writeUserDev(fd); err != nil {
+			return err
+		}
+	default:
+		return e1
+	}
+
+	if _, err := unix.IoctlRetInt(fd, UI_DEV_CREATE); err != nil {
+		return err
+	}
+	return nil
+}
+
+// writeUserDev declares the identity and absolute axes of a uinput device with the legacy setup struct.
+// The axis resolutions are not supported.
+func (b *Builder) writeUserDev(fd int) error {
+	dev := UserDev{ID: b.id}
+	copy(dev.Name[:], b.deviceName())
+	for axis, info := range b.abs {
+		dev.AbsMax[axis] = info.Maximum
+		dev.AbsMin[axis] = info.Minimum
+		dev.AbsFuzz[axis] = info.Fuzz
+		dev.AbsFlat[axis] = info.Flat
+	}
+	buf := (*[unsafe.Sizeof(dev)]byte)(unsafe.Pointer(&dev))[:]
+	n, err := unix.Write(fd, buf)
+	if err != nil {
+		return err
+	}
+	if n != len(buf) {
+		return fmt.Errorf("short write of uinput_user_dev: %d of %d bytes", n, len(buf))
+	}
+	return nil
+}
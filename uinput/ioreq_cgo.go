@@ -6,6 +6,12 @@ package uinput
 #include <linux/uinput.h>
 
 int uinput_setup_size = sizeof(struct uinput_setup);
+int uinput_abs_setup_size = sizeof(struct uinput_abs_setup);
+int uinput_user_dev_size = sizeof(struct uinput_user_dev);
+
+unsigned int _UI_GET_SYSNAME(unsigned int len) {
+	return UI_GET_SYSNAME(len);
+}
 */
 import "C"
 import "unsafe"
@@ -13,12 +19,17 @@ import "unsafe"
 const MaxNameSize = C.UINPUT_MAX_NAME_SIZE
 
 var setupStructSize = C.uinput_setup_size
+var absSetupStructSize = C.uinput_abs_setup_size
+var userDevStructSize = C.uinput_user_dev_size
 
 var (
 	UI_DEV_CREATE  = uint(C.UI_DEV_CREATE)
 	UI_DEV_DESTROY = uint(C.UI_DEV_DESTROY)
 
 	UI_DEV_SETUP = uint(C.UI_DEV_SETUP)
+	UI_ABS_SETUP = uint(C.UI_ABS_SETUP)
+
+	UI_GET_VERSION = uint(C.UI_GET_VERSION)
 
 	UI_SET_EVBIT   = uint(C.UI_SET_EVBIT)
 	UI_SET_KEYBIT  = uint(C.UI_SET_KEYBIT)
@@ -33,6 +44,10 @@ var (
 	UI_SET_PROPBIT = uint(C.UI_SET_PROPBIT)
 )
 
+func UI_GET_SYSNAME(len uint) uint {
+	return uint(C._UI_GET_SYSNAME(C.uint(len)))
+}
+
 func structSizeMismatch()
 
 func init() {
@@ -21,6 +21,9 @@ var (
 	UI_DEV_DESTROY = ioc.IO(UINPUT_IOCTL_BASE, 2)
 
 	UI_DEV_SETUP = ioc.IOW(UINPUT_IOCTL_BASE, 3, 8+80+4)
+	UI_ABS_SETUP = ioc.IOW(UINPUT_IOCTL_BASE, 4, 2+2+24)
+
+	UI_GET_VERSION = ioc.IOR(UINPUT_IOCTL_BASE, 45, 4)
 
 	UI_SET_EVBIT   = ioc.IOW(UINPUT_IOCTL_BASE, 100, 4)
 	UI_SET_KEYBIT  = ioc.IOW(UINPUT_IOCTL_BASE, 101, 4)
@@ -34,3 +37,7 @@ var (
 	UI_SET_SWBIT   = ioc.IOW(UINPUT_IOCTL_BASE, 109, 4)
 	UI_SET_PROPBIT = ioc.IOW(UINPUT_IOCTL_BASE, 110, 4)
 )
+
+func UI_GET_SYSNAME(len uint) uint {
+	return ioc.IOC(ioc.Read, UINPUT_IOCTL_BASE, 44, len)
+}
@@ -2,13 +2,17 @@
 package uinput
 
 import (
+	"errors"
+	"fmt"
 	"os"
-	"syscall"
+	"path/filepath"
+	"time"
 	"unsafe"
 
 	"github.com/erdichen/chromekey/evdev"
 	"github.com/erdichen/chromekey/evdev/eventcode"
 	"github.com/erdichen/chromekey/evdev/keycode"
+	"github.com/erdichen/chromekey/ioc"
 	"github.com/erdichen/chromekey/log"
 	"golang.org/x/sys/unix"
 )
@@ -21,91 +25,163 @@ type Setup struct {
 	FFEffectsMax uint32
 }
 
+// UserDev is the legacy uinput device setup struct for kernels without UI_DEV_SETUP.
+type UserDev struct {
+	Name [MaxNameSize]byte
+	ID   evdev.InputID
+
+	FFEffectsMax uint32
+
+	AbsMax  [keycode.Abs_ABS_CNT]int32
+	AbsMin  [keycode.Abs_ABS_CNT]int32
+	AbsFuzz [keycode.Abs_ABS_CNT]int32
+	AbsFlat [keycode.Abs_ABS_CNT]int32
+}
+
 // Device is a virtual keyboard device.
 type Device struct {
-	f *os.File
+	f    *os.File
+	info evdev.Info // Identity recorded by AddRemapDevice
+}
+
+// AbsSetup is a uinput absolute axis setup request info struct.
+type AbsSetup struct {
+	Code    uint16
+	_       uint16
+	AbsInfo evdev.AbsInfo
+}
+
+// remapKeys are the keys sent by the default key maps that a keyboard may not have.
+var remapKeys = []keycode.Code{
+	keycode.Code_KEY_BACK,
+	keycode.Code_KEY_FORWARD,
+	keycode.Code_KEY_REFRESH,
+	keycode.Code_KEY_SEARCH,
+	keycode.Code_KEY_BRIGHTNESSDOWN,
+	keycode.Code_KEY_BRIGHTNESSUP,
+	keycode.Code_KEY_KBDILLUMDOWN,
+	keycode.Code_KEY_KBDILLUMUP,
+	keycode.Code_KEY_MUTE,
+	keycode.Code_KEY_VOLUMEDOWN,
+	keycode.Code_KEY_VOLUMEUP,
+}
+
+// AddRemapCapabilities adds the keys, scancodes and LEDs sent by the remapper.
+func (b *Builder) AddRemapCapabilities() *Builder {
+	b.AddKeys(remapKeys...)
+	b.AddMisc(eventcode.MSC_SCAN)
+	// Pretend we have all these LEDs.
+	for i := keycode.LED(0); i < keycode.LED_CNT; i++ {
+		b.AddLEDs(i)
+	}
+	return b
 }
 
 // CreateDevice creates a virtual keyboard device with the keycodes set in keyBits.
 func CreateDevice(device string, keyBits *keycode.KeyBits) (*Device, error) {
-	f, err := os.OpenFile(device, os.O_RDWR|unix.O_NONBLOCK, 0644)
+	return NewBuilder().AddKeyBits(keyBits).AddRemapCapabilities().Create(device)
+}
+
+// CreateFromDevice creates a virtual keyboard devices that replicates the capabilities of an input device.
+func CreateFromDevice(device string, in *evdev.Device) (*Device, error) {
+	b, err := NewBuilderFromDevice(in)
 	if err != nil {
+		log.Errorf("failed to get capabilities from evdev device: %v", err)
 		return nil, err
 	}
-	evTypes := []eventcode.EventType{eventcode.EV_SYN, eventcode.EV_KEY, eventcode.EV_MSC, eventcode.EV_LED}
-	for _, t := range evTypes {
-		if err := unix.IoctlSetInt(int(f.Fd()), UI_SET_EVBIT, int(t)); err != nil {
-			return nil, err
-		}
-	}
+	return b.AddRemapCapabilities().Create(device)
+}
 
-	if err := unix.IoctlSetInt(int(f.Fd()), UI_SET_MSCBIT, int(eventcode.MSC_SCAN)); err != nil {
-		return nil, err
+// control runs a function with the file descriptor of a uinput file.
+// Unlike Fd, it keeps the file in non-blocking mode so that closing the file wakes up a blocked ReadEvents.
+func control(f *os.File, fn func(fd int) error) error {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return err
 	}
-
-	for i := 0; i < int(keycode.LED_CNT); i++ {
-		if err := unix.IoctlSetInt(int(f.Fd()), UI_SET_LEDBIT, i); err != nil {
-			return nil, err
-		}
+	var ferr error
+	if err := rc.Control(func(fd uintptr) { ferr = fn(int(fd)) }); err != nil {
+		return err
 	}
+	return ferr
+}
 
-	// Pretend we have all these LEDs.
-	keyBits.Set(keycode.Code_KEY_BACK, true)
-	keyBits.Set(keycode.Code_KEY_FORWARD, true)
-	keyBits.Set(keycode.Code_KEY_REFRESH, true)
-	keyBits.Set(keycode.Code_KEY_SEARCH, true)
-	keyBits.Set(keycode.Code_KEY_BRIGHTNESSDOWN, true)
-	keyBits.Set(keycode.Code_KEY_BRIGHTNESSUP, true)
-	keyBits.Set(keycode.Code_KEY_KBDILLUMDOWN, true)
-	keyBits.Set(keycode.Code_KEY_KBDILLUMUP, true)
-	keyBits.Set(keycode.Code_KEY_MUTE, true)
-	keyBits.Set(keycode.Code_KEY_VOLUMEDOWN, true)
-	keyBits.Set(keycode.Code_KEY_VOLUMEUP, true)
-
-	for i, b := range keyBits {
-		for j := 0; j < 8; j++ {
-			if (b & (1 << j)) != 0 {
-				k := i*8 + j
-				// log.Infof("event code %v\n", keycode.Code(k))
-				if err := unix.IoctlSetInt(int(f.Fd()), UI_SET_KEYBIT, k); err != nil {
-					return nil, err
-				}
-			}
-		}
+// Close closes the virtual keyboard devices.
+func (dev *Device) Close() error {
+	err := control(dev.f, func(fd int) error {
+		_, err := unix.IoctlRetInt(fd, UI_DEV_DESTROY)
+		return err
+	})
+	if err != nil {
+		return err
 	}
+	evdev.RemoveRemapDevice(dev.info.Name, dev.info.Phys, dev.info.ID)
+	return dev.f.Close()
+}
 
-	setup := Setup{
-		ID: evdev.InputID{BusType: 3, Vendor: 1, Product: 1, Version: 9999},
-	}
-	copy(setup.Name[:], "Chromebook keyboard remap")
-	_, _, e1 := syscall.Syscall(syscall.SYS_IOCTL, uintptr(f.Fd()), uintptr(UI_DEV_SETUP), uintptr(unsafe.Pointer(&setup)))
-	if e1 != 0 {
-		return nil, e1
-	}
+// Version returns the version of the uinput driver.
+func (dev *Device) Version() (int, error) {
+	var v int
+	err := control(dev.f, func(fd int) error {
+		var err error
+		v, err = unix.IoctlGetInt(fd, UI_GET_VERSION)
+		return err
+	})
+	return v, err
+}
 
-	if _, err := unix.IoctlRetInt(int(f.Fd()), UI_DEV_CREATE); err != nil {
-		return nil, err
+// SysName returns the name of the device in /sys/devices/virtual/input, e.g. "input23".
+func (dev *Device) SysName() (string, error) {
+	var buf [64]byte
+	err := control(dev.f, func(fd int) error {
+		return ioc.Ioctl(fd, UI_GET_SYSNAME(uint(len(buf))), uintptr(unsafe.Pointer(&buf[0])))
+	})
+	if err != nil {
+		return "", err
 	}
-
-	return &Device{f: f}, nil
+	for i, v := range buf {
+		if v == 0 {
+			return string(buf[:i]), nil
+		}
+	}
+	return string(buf[:]), nil
 }
 
-// CreateFromDevice creates a virtual keyboard devices that supports the same set of keycodes as an input device.
-func CreateFromDevice(device string, in *evdev.Device) (*Device, error) {
-	bits, err := in.GetKeyBits()
+// EventPath returns the path of the event device node of the device in devDir, e.g. /dev/input/event23.
+// It waits up to timeout for udev to create the node and make it accessible.
+func (dev *Device) EventPath(devDir string, timeout time.Duration) (string, error) {
+	sysName, err := dev.SysName()
 	if err != nil {
-		log.Errorf("failed to get key bits from evdev device: %v", err)
-		return nil, err
+		return "", err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		var path string
+		events, err := filepath.Glob(filepath.Join("/sys/devices/virtual/input", sysName, "event*"))
+		if err == nil && len(events) > 0 {
+			path = filepath.Join(devDir, filepath.Base(events[0]))
+			if err = unix.Access(path, unix.R_OK|unix.W_OK); err == nil {
+				return path, nil
+			}
+		} else if err == nil {
+			err = errors.New("no event device")
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("failed to find the event device node of %v: %v", sysName, err)
+		}
+		time.Sleep(10 * time.Millisecond)
 	}
-	return CreateDevice(device, bits)
 }
 
-// Close closes the virtual keyboard devices.
-func (dev *Device) Close() error {
-	if _, err := unix.IoctlRetInt(int(dev.f.Fd()), UI_DEV_DESTROY); err != nil {
-		return err
+// ReadEvents reads the input events sent to a virtual keyboard device, e.g. the LED states set by the desktop.
+func (dev *Device) ReadEvents() ([]evdev.InputEvent, error) {
+	buf := [evdev.EventSize * 64]byte{}
+
+	n, err := dev.f.Read(buf[:])
+	if err != nil {
+		return nil, err
 	}
-	return dev.f.Close()
+	return evdev.UnmarshalEvents(buf[:n])
 }
 
 // WriteEvents writes input events to a virtual keyboard device.
@@ -1,8 +1,15 @@
 package uinput
 
 import (
+	"errors"
+	"os"
 	"testing"
+	"time"
 	"unsafe"
+
+	"github.com/erdichen/chromekey/evdev"
+	"github.com/erdichen/chromekey/evdev/eventcode"
+	"github.com/erdichen/chromekey/evdev/keycode"
 )
 
 func TestUinput(t *testing.T) {
@@ -11,4 +18,61 @@ func TestUinput(t *testing.T) {
 	if got != want {
 		t.Errorf("bad uinput_setup size: got %d want %d", got, want)
 	}
+	got = unsafe.Sizeof(AbsSetup{})
+	want = uintptr(absSetupStructSize)
+	if got != want {
+		t.Errorf("bad uinput_abs_setup size: got %d want %d", got, want)
+	}
+	got = unsafe.Sizeof(UserDev{})
+	want = uintptr(userDevStructSize)
+	if got != want {
+		t.Errorf("bad uinput_user_dev size: got %d want %d", got, want)
+	}
+}
+
+func TestAddCapabilities(t *testing.T) {
+	var keys keycode.KeyBits
+	keys.Set(keycode.Code_KEY_A, true)
+	c := &evdev.Capabilities{
+		Types: []eventcode.EventType{eventcode.EV_SYN, eventcode.EV_KEY, eventcode.EV_REP, eventcode.EV_FF},
+		Keys:  &keys,
+	}
+	b := NewBuilder().AddCapabilities(c)
+	if !b.types[eventcode.EV_KEY] || !b.keys.Get(keycode.Code_KEY_A) {
+		t.Errorf("AddCapabilities: missing EV_KEY KEY_A")
+	}
+	if b.types[eventcode.EV_REP] || b.types[eventcode.EV_FF] {
+		t.Errorf("AddCapabilities: got EV_REP or EV_FF want none")
+	}
+	if b.types[eventcode.EV_REL] {
+		t.Errorf("AddCapabilities: got EV_REL want none")
+	}
+}
+
+func TestControl(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	defer w.Close()
+	if err := control(r, func(fd int) error { return nil }); err != nil {
+		t.Fatalf("control: %v", err)
+	}
+
+	// Closing the file wakes up a blocked read like closing a virtual keyboard wakes up ReadEvents.
+	errC := make(chan error)
+	go func() {
+		_, err := r.Read(make([]byte, 1))
+		errC <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	r.Close()
+	select {
+	case err := <-errC:
+		if !errors.Is(err, os.ErrClosed) {
+			t.Errorf("read after close: got %v want %v", err, os.ErrClosed)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("read after close: still blocked")
+	}
 }